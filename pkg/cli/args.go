@@ -4,7 +4,6 @@
 package cli
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -22,6 +21,15 @@ func parseArgs(c *Command, args []string) error {
 	}
 	if a := c; a != nil {
 		l := makeArgList(c)
+		var err error
+		if args, err = expandInputSources(c, l, args); err != nil {
+			return err
+		}
+		if shouldPrompt(c) {
+			if args, err = promptForArgs(c, l, args); err != nil {
+				return err
+			}
+		}
 		return l.parseArgs(args)
 	}
 	if len(args) != 0 {
@@ -40,16 +48,19 @@ func makeArgList(c *Command) ArgList {
 }
 
 type Arg struct {
-	name string
+	name     string
+	typeName string
 
 	required   bool
 	defaultVal string
-	val        *string
+	set        func(string) error
+	setZero    func()
 
 	variadic    bool
-	listVal     *[]string
+	setList     func([]string) error
 	defaultVals []string
 	minVals     int
+	fromInput   bool
 }
 
 type ArgList []Arg
@@ -61,31 +72,46 @@ func (al *ArgList) parseArgs(args []string) error {
 		}
 		if len(args) < i+1 {
 			if a.required {
-				return fmt.Errorf("required argument missing: %s", a.name)
+				return &MissingArgError{Name: a.name}
+			}
+			if a.defaultVal == "" {
+				a.setZero()
+				return nil
 			}
-			*a.val = a.defaultVal
-			return nil
-		} else {
-			*a.val = args[i]
+			return a.setValue(a.defaultVal)
+		} else if err := a.setValue(args[i]); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+// displayName returns the arg's name along with its type if it has one.
+func (a Arg) displayName(suffix string) string {
+	if a.typeName == "" {
+		return a.name + suffix
+	}
+	return a.name + suffix + ":" + a.typeName
+}
+
+func (a Arg) setValue(value string) error {
+	if err := a.set(value); err != nil {
+		return &InvalidArgError{Name: a.name, Value: value, Err: err}
+	}
+	return nil
+}
+
 func (a Arg) parseVariadic(args []string) error {
 	if !a.required {
 		if len(args) != 0 {
-			(*a.listVal) = args
-		} else {
-			(*a.listVal) = a.defaultVals
+			return a.setList(args)
 		}
-		return nil
+		return a.setList(a.defaultVals)
 	}
 	if len(args) < a.minVals {
-		return fmt.Errorf("required %s argument(s) missing; you must supply at least %d", a.name, a.minVals)
+		return &MissingArgError{Name: a.name, Min: a.minVals}
 	}
-	(*a.listVal) = args
-	return nil
+	return a.setList(args)
 }
 
 func (al *ArgList) assertLast() {
@@ -108,20 +134,17 @@ func (al *ArgList) add(a Arg) {
 }
 
 func (al *ArgList) Required(val *string, name string) {
-	al.add(Arg{val: val, name: name, required: true})
+	RequiredArg(al, val, name, StringParser)
 }
 
 func (al *ArgList) Optional(val *string, name, defaultVal string) {
-	al.add(Arg{val: val, name: name, defaultVal: defaultVal})
+	OptionalArg(al, val, name, StringParser, defaultVal)
 }
 
 func (al *ArgList) OptionalVariadic(vals *[]string, name string, defaultVals ...string) {
-	al.add(Arg{variadic: true, listVal: vals, name: name, defaultVals: defaultVals})
+	OptionalVariadicArg(al, vals, name, StringParser, defaultVals...)
 }
 
 func (al *ArgList) RequiredVariadic(vals *[]string, name string, minimumVals int) {
-	if minimumVals < 1 {
-		panic(fmt.Sprintf("cannot require %d values; must be > 0", minimumVals))
-	}
-	al.add(Arg{variadic: true, listVal: vals, name: name, required: true, minVals: minimumVals})
+	RequiredVariadicArg(al, vals, name, minimumVals, StringParser)
 }
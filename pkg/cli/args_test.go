@@ -5,7 +5,11 @@ package cli
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/composite-action-framework-go/pkg/testhelpers/assert"
 )
@@ -305,3 +309,172 @@ func TestArgList_parseArgs(t *testing.T) {
 	}
 
 }
+
+type mode string
+
+func (m *mode) UnmarshalText(b []byte) error {
+	*m = mode(strings.ToUpper(string(b)))
+	return nil
+}
+
+func TestTypedArgs(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	type typedOpts struct {
+		Count   int
+		Mode    mode
+		Color   string
+		Dir     string
+		Timeout time.Duration
+		Files   []string
+	}
+	setup := func(o *typedOpts, al *ArgList) {
+		RequiredArg(al, &o.Count, "count", IntParser)
+		RequiredArg(al, &o.Mode, "mode", TextParser[mode]("mode"))
+		RequiredArg(al, &o.Color, "color", EnumParser("red", "green"))
+		RequiredArg(al, &o.Dir, "dir", ExistingDirParser)
+		OptionalArg(al, &o.Timeout, "timeout", DurationParser, "1m")
+	}
+
+	cases := []struct {
+		desc    string
+		args    []string
+		want    *typedOpts
+		wantErr string
+	}{
+		{
+			"valid",
+			[]string{"3", "fast", "red", dir},
+			&typedOpts{Count: 3, Mode: "FAST", Color: "red", Dir: dir, Timeout: time.Minute},
+			"",
+		},
+		{
+			"invalid_int",
+			[]string{"three", "fast", "red", dir},
+			nil,
+			`invalid value "three" for argument COUNT: strconv.Atoi: parsing "three": invalid syntax`,
+		},
+		{
+			"invalid_enum",
+			[]string{"3", "fast", "blue", dir},
+			nil,
+			`invalid value "blue" for argument COLOR: must be one of: red, green`,
+		},
+		{
+			"missing_dir",
+			[]string{"3", "fast", "red", filepath.Join(dir, "nope")},
+			nil,
+			`invalid value "` + filepath.Join(dir, "nope") + `" for argument DIR: directory does not exist`,
+		},
+		{
+			"dir_is_file",
+			[]string{"3", "fast", "red", file},
+			nil,
+			`invalid value "` + file + `" for argument DIR: directory does not exist`,
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			al := new(ArgList)
+			got := new(typedOpts)
+			setup(got, al)
+			err := al.parseArgs(c.args)
+			if c.wantErr != "" {
+				if err == nil || err.Error() != c.wantErr {
+					t.Fatalf("got error %v; want %q", err, c.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, got, c.want)
+		})
+	}
+}
+
+func TestParsers(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "nope")
+	parse := func(p Parser[string]) func(string) (any, error) {
+		return func(s string) (any, error) { return p.Parse(s) }
+	}
+	parseBool := func(s string) (any, error) { return BoolParser.Parse(s) }
+
+	cases := []struct {
+		desc    string
+		parse   func(string) (any, error)
+		input   string
+		want    any
+		wantErr string
+	}{
+		{"file", parse(ExistingFileParser), file, file, ""},
+		{"file_missing", parse(ExistingFileParser), missing, nil, "file does not exist"},
+		{"file_is_dir", parse(ExistingFileParser), dir, nil, "file does not exist"},
+		{"dir", parse(ExistingDirParser), dir, dir, ""},
+		{"dir_missing", parse(ExistingDirParser), missing, nil, "directory does not exist"},
+		{"dir_is_file", parse(ExistingDirParser), file, nil, "directory does not exist"},
+		{"bool_true", parseBool, "true", true, ""},
+		{"bool_1", parseBool, "1", true, ""},
+		{"bool_false", parseBool, "false", false, ""},
+		{"bool_invalid", parseBool, "yes", nil, `strconv.ParseBool: parsing "yes": invalid syntax`},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			got, err := c.parse(c.input)
+			if c.wantErr != "" {
+				if err == nil || err.Error() != c.wantErr {
+					t.Fatalf("got error %v; want %q", err, c.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("got %v; want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestTypedArgs_variadic(t *testing.T) {
+	var got []int
+	al := new(ArgList)
+	RequiredVariadicArg(al, &got, "nums", 1, IntParser)
+	if err := al.parseArgs([]string{"1", "2", "x"}); err == nil || err.Error() != `invalid value "x" for argument NUMS: strconv.Atoi: parsing "x": invalid syntax` {
+		t.Fatalf("got error %v", err)
+	}
+	if err := al.parseArgs([]string{"1", "2"}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, got, []int{1, 2})
+}
+
+func TestSynopsis_typedArgs(t *testing.T) {
+	c := LeafCommand("leaf", "leaf", func(*typedSynopsisOpts) error { return nil })
+	want := "<COUNT:int>[COLOR:red|green (red)]"
+	if got := c.Synopsis(); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+type typedSynopsisOpts struct {
+	count int
+	color string
+}
+
+func (o *typedSynopsisOpts) Args(al *ArgList) {
+	RequiredArg(al, &o.count, "count", IntParser)
+	OptionalArg(al, &o.color, "color", EnumParser("red", "green"), "red")
+}
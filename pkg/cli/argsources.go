@@ -0,0 +1,123 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// RequiredVariadicFromInput is like RequiredVariadic, except that values may
+// also be read from stdin or files, see RequiredVariadicArgFromInput.
+func (al *ArgList) RequiredVariadicFromInput(vals *[]string, name string, minimumVals int) {
+	RequiredVariadicArgFromInput(al, vals, name, minimumVals, StringParser)
+}
+
+// OptionalVariadicFromInput is like OptionalVariadic, except that values may
+// also be read from stdin or files, see RequiredVariadicArgFromInput.
+func (al *ArgList) OptionalVariadicFromInput(vals *[]string, name string, defaultVals ...string) {
+	OptionalVariadicArgFromInput(al, vals, name, StringParser, defaultVals...)
+}
+
+// RequiredVariadicArgFromInput is like RequiredVariadicArg, except that any of
+// the arg's values may be replaced by a source of values:
+//
+//   - "-" reads values from the command's stdin. This may only be given once.
+//   - "@path" reads values from the file at path.
+//
+// Values in sources are separated by newlines, or by NUL characters if there
+// are any, as output by e.g. find -print0. Empty values are ignored, as are
+// carriage returns before newlines. A literal value starting with @ can be
+// given by doubling it, e.g. "@@name" for "@name".
+//
+// The minimum number of values applies after sources have been read.
+func RequiredVariadicArgFromInput[T any](al *ArgList, vals *[]T, name string, minimumVals int, p Parser[T]) {
+	RequiredVariadicArg(al, vals, name, minimumVals, p)
+	(*al)[len(*al)-1].fromInput = true
+}
+
+// OptionalVariadicArgFromInput is like OptionalVariadicArg, except that values
+// may also be read from stdin or files, see RequiredVariadicArgFromInput.
+func OptionalVariadicArgFromInput[T any](al *ArgList, vals *[]T, name string, p Parser[T], defaultVals ...string) {
+	OptionalVariadicArg(al, vals, name, p, defaultVals...)
+	(*al)[len(*al)-1].fromInput = true
+}
+
+// expandInputSources replaces any sources in args given for variadic args that
+// accept them with the values they contain.
+func expandInputSources(c *Command, al ArgList, args []string) ([]string, error) {
+	if len(al) == 0 {
+		return args, nil
+	}
+	last := al[len(al)-1]
+	start := len(al) - 1
+	if !last.fromInput || len(args) <= start {
+		return args, nil
+	}
+	expanded := append([]string{}, args[:start]...)
+	readStdin := false
+	for _, arg := range args[start:] {
+		var values []string
+		var err error
+		switch {
+		case arg == "-":
+			if readStdin {
+				return nil, fmt.Errorf("%s: stdin (-) can only be given once", last.name)
+			}
+			readStdin = true
+			values, err = readInputValues(c.stdin)
+		case strings.HasPrefix(arg, "@@"):
+			values = []string{arg[1:]}
+		case strings.HasPrefix(arg, "@") && len(arg) > 1:
+			values, err = readInputFile(arg[1:])
+		default:
+			values = []string{arg}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: reading %s: %w", last.name, arg, err)
+		}
+		expanded = append(expanded, values...)
+	}
+	return expanded, nil
+}
+
+func readInputFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	values, err := readInputValues(f)
+	return values, errors.Join(err, f.Close())
+}
+
+// readInputValues reads newline or NUL separated values from r.
+func readInputValues(r io.Reader) ([]string, error) {
+	if r == nil {
+		return nil, nil
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var items []string
+	if bytes.IndexByte(b, 0) != -1 {
+		items = strings.Split(string(b), "\x00")
+	} else {
+		items = strings.Split(string(b), "\n")
+		for i, item := range items {
+			items[i] = strings.TrimSuffix(item, "\r")
+		}
+	}
+	var values []string
+	for _, item := range items {
+		if item != "" {
+			values = append(values, item)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,89 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type inputArgsOpts struct {
+	dest  string
+	paths []string
+}
+
+func (o *inputArgsOpts) Args(al *ArgList) {
+	al.Required(&o.dest, "dest")
+	al.RequiredVariadicFromInput(&o.paths, "path", 2)
+}
+
+type inputIntArgsOpts struct {
+	nums []int
+}
+
+func (o *inputIntArgsOpts) Args(al *ArgList) {
+	OptionalVariadicArgFromInput(al, &o.nums, "num", IntParser, "0")
+}
+
+func TestVariadicArgFromInput(t *testing.T) {
+	dir := t.TempDir()
+	listFile := filepath.Join(dir, "list.txt")
+	if err := os.WriteFile(listFile, []byte("f1\r\nf2\n\nf3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		args    []string
+		stdin   string
+		want    string
+		wantErr string
+	}{
+		{args("paths", "d", "a", "b"), "", "d [a b]", ""},
+		{args("paths", "d", "-"), "a\nb\n", "d [a b]", ""},
+		{args("paths", "d", "-"), "a b\x00c\nd\x00", "d [a b c\nd]", ""},
+		{args("paths", "d", "x", "-", "y"), "a\n", "d [x a y]", ""},
+		{args("paths", "d", "@"+listFile), "", "d [f1 f2 f3]", ""},
+		{args("paths", "d", "@@literal", "@"+listFile), "", "d [@literal f1 f2 f3]", ""},
+		{args("paths", "-", "a", "b"), "", "- [a b]", ""},
+		{args("paths", "d", "-"), "a\n", "", "required PATH argument(s) missing; you must supply at least 2"},
+		{args("paths", "d", "-", "-"), "a\n", "", "PATH: stdin (-) can only be given once"},
+		{args("paths", "d", "@"+filepath.Join(dir, "nope")), "", "",
+			fmt.Sprintf("PATH: reading @%s: open %s: no such file or directory", filepath.Join(dir, "nope"), filepath.Join(dir, "nope"))},
+		{args("nums"), "", "[0]", ""},
+		{args("nums", "-"), "1\n2\n", "[1 2]", ""},
+		{args("nums", "-"), "1\nx\n", "", `invalid value "x" for argument NUM: strconv.Atoi: parsing "x": invalid syntax`},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(strings.Join(c.args[1:], " "), func(t *testing.T) {
+			var got string
+			root := RootCommand("root", "root command",
+				LeafCommand("paths", "paths", func(o *inputArgsOpts) error {
+					got = fmt.Sprintf("%s %v", o.dest, o.paths)
+					return nil
+				}),
+				LeafCommand("nums", "nums", func(o *inputIntArgsOpts) error {
+					got = fmt.Sprint(o.nums)
+					return nil
+				}),
+			)
+			root.SetStdin(strings.NewReader(c.stdin))
+			err := root.Execute(c.args)
+			if c.wantErr != "" {
+				if err == nil || err.Error() != c.wantErr {
+					t.Fatalf("got error %v; want %q", err, c.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("got %q; want %q", got, c.want)
+			}
+		})
+	}
+}
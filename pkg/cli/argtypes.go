@@ -0,0 +1,137 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"encoding"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/composite-action-framework-go/pkg/fs"
+)
+
+// Parser parses and validates a single arg value as a T.
+// TypeName is shown alongside the arg's name in the command's synopsis;
+// if it's empty then no type is shown.
+type Parser[T any] struct {
+	TypeName string
+	Parse    func(string) (T, error)
+}
+
+var (
+	StringParser   = Parser[string]{"", func(s string) (string, error) { return s, nil }}
+	IntParser      = Parser[int]{"int", strconv.Atoi}
+	Int64Parser    = Parser[int64]{"int", func(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) }}
+	Float64Parser  = Parser[float64]{"float", func(s string) (float64, error) { return strconv.ParseFloat(s, 64) }}
+	BoolParser     = Parser[bool]{"bool", strconv.ParseBool}
+	DurationParser = Parser[time.Duration]{"duration", time.ParseDuration}
+)
+
+// TextParser returns a Parser for any type whose pointer implements
+// encoding.TextUnmarshaler.
+func TextParser[T any, PT interface {
+	*T
+	encoding.TextUnmarshaler
+}](typeName string) Parser[T] {
+	return Parser[T]{typeName, func(s string) (T, error) {
+		var v T
+		err := PT(&v).UnmarshalText([]byte(s))
+		return v, err
+	}}
+}
+
+// EnumParser returns a Parser that only accepts one of the allowed values.
+func EnumParser(allowed ...string) Parser[string] {
+	return Parser[string]{strings.Join(allowed, "|"), func(s string) (string, error) {
+		for _, a := range allowed {
+			if s == a {
+				return s, nil
+			}
+		}
+		return "", fmt.Errorf("must be one of: %s", strings.Join(allowed, ", "))
+	}}
+}
+
+// ExistingFileParser only accepts paths to files that exist.
+var ExistingFileParser = Parser[string]{"file", func(s string) (string, error) {
+	return s, assertExists(s, "file", fs.FileExists)
+}}
+
+// ExistingDirParser only accepts paths to directories that exist.
+var ExistingDirParser = Parser[string]{"dir", func(s string) (string, error) {
+	return s, assertExists(s, "directory", fs.DirExists)
+}}
+
+func assertExists(path, kind string, exists func(string) (bool, error)) error {
+	ok, err := exists(path)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s does not exist", kind)
+	}
+	return nil
+}
+
+func setters[T any](val *T, p Parser[T]) (set func(string) error, setZero func()) {
+	set = func(s string) error {
+		v, err := p.Parse(s)
+		if err != nil {
+			return err
+		}
+		*val = v
+		return nil
+	}
+	setZero = func() {
+		var zero T
+		*val = zero
+	}
+	return set, setZero
+}
+
+func listSetter[T any](vals *[]T, name string, p Parser[T]) func([]string) error {
+	return func(ss []string) error {
+		var out []T
+		for _, s := range ss {
+			v, err := p.Parse(s)
+			if err != nil {
+				return &InvalidArgError{Name: strings.ToUpper(name), Value: s, Err: err}
+			}
+			out = append(out, v)
+		}
+		*vals = out
+		return nil
+	}
+}
+
+// RequiredArg adds a required arg parsed by p to al.
+func RequiredArg[T any](al *ArgList, val *T, name string, p Parser[T]) {
+	set, setZero := setters(val, p)
+	al.add(Arg{name: name, typeName: p.TypeName, required: true, set: set, setZero: setZero})
+}
+
+// OptionalArg adds an optional arg parsed by p to al. If the arg isn't
+// supplied, defaultVal is parsed instead, or if defaultVal is empty then val
+// is set to its zero value.
+func OptionalArg[T any](al *ArgList, val *T, name string, p Parser[T], defaultVal string) {
+	set, setZero := setters(val, p)
+	al.add(Arg{name: name, typeName: p.TypeName, defaultVal: defaultVal, set: set, setZero: setZero})
+}
+
+// RequiredVariadicArg adds a variadic arg parsed by p to al, which must have at
+// least minimumVals values.
+func RequiredVariadicArg[T any](al *ArgList, vals *[]T, name string, minimumVals int, p Parser[T]) {
+	if minimumVals < 1 {
+		panic(fmt.Sprintf("cannot require %d values; must be > 0", minimumVals))
+	}
+	al.add(Arg{variadic: true, name: name, typeName: p.TypeName, required: true, minVals: minimumVals, setList: listSetter(vals, name, p)})
+}
+
+// OptionalVariadicArg adds a variadic arg parsed by p to al. If no values are
+// supplied, defaultVals are parsed instead.
+func OptionalVariadicArg[T any](al *ArgList, vals *[]T, name string, p Parser[T], defaultVals ...string) {
+	al.add(Arg{variadic: true, name: name, typeName: p.TypeName, defaultVals: defaultVals, setList: listSetter(vals, name, p)})
+}
@@ -5,11 +5,13 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"flag"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"time"
 )
 
 // Command represents a command in the CLI graph.
@@ -17,30 +19,79 @@ import (
 // and LeafCommand functions to construct root and leaf commands.
 type Command struct {
 	name, desc, help string
-	run              func() error
+	aliases          []string
+	run              func(context.Context) error
 	optionSet
 	subs   []*Command
 	parent *Command
 
 	hideFlagsFromSynopsis map[string]any
+	flagEnv               bool
+	gnuFlags              bool
+	prefixMatching        bool
+	prompting             bool
+	hidden                bool
+	deprecated            bool
+	experimental          bool
+	replacement           string
+	category              string
+	examples              []Example
+	gracePeriod           time.Duration
+	persistent            Flags
+	configFlag, configEnv string
+	hasOutput             bool
+	middleware            []Middleware
+	preRun                []PreRunHook
+	postRun               []PostRunHook
+	// runConcurrently is set for leaves run by ForEachCommand, which mustn't
+	// register or set their ancestors' shared persistent flags.
+	runConcurrently bool
 
 	// Runtime
-	flagSet        *flag.FlagSet
-	stdout, stderr io.Writer
-	stdin          io.Reader
+	flagSet          *flag.FlagSet
+	flagUsages       map[string]string
+	persistentNames  map[string]bool
+	persistentValues map[string]inheritedFlag
+	configPath       string
+	outputFormat     string
+	prompter         *prompter
+	stdout, stderr   io.Writer
+	stdin            io.Reader
+	lookupEnv        func(string) (string, bool)
 }
 
 func (c *Command) Name() string        { return c.name }
 func (c *Command) Description() string { return c.desc }
+
+// Help returns c's full help, as printed by -h.
 func (c *Command) Help() string {
-	return strings.TrimSpace(fmt.Sprintf("%s\n\n%s", c.Usage(), strings.TrimSpace(c.help)))
+	buf := &strings.Builder{}
+	_ = c.printHelp(buf)
+	return strings.TrimSpace(buf.String())
+}
+
+func (c *Command) Run() func() error {
+	if c.run == nil {
+		return nil
+	}
+	return func() error { return c.run(context.Background()) }
 }
-func (c *Command) Run() func() error       { return c.run }
 func (c *Command) Flags() Flags            { return c.flags }
 func (c *Command) Args() Args              { return c.args }
 func (c *Command) Env() Env                { return c.env }
 func (c *Command) Init() Init              { return c.init }
 func (c *Command) Subcommands() []*Command { return c.subs }
+func (c *Command) Stdout() io.Writer       { return c.stdout }
+func (c *Command) Stderr() io.Writer       { return c.stderr }
+func (c *Command) Stdin() io.Reader        { return c.stdin }
+
+// LookupEnv looks up an environment variable using the function set by
+// SetLookupEnv, which defaults to os.LookupEnv.
+func (c *Command) LookupEnv(name string) (string, bool) { return c.lookupEnv(name) }
+
+// LookupEnvFunc returns the function set by SetLookupEnv, so that callers
+// replacing it temporarily can restore it.
+func (c *Command) LookupEnvFunc() func(string) (string, bool) { return c.lookupEnv }
 
 func (c *Command) Path() []string {
 	curr := []string{c.Name()}
@@ -57,52 +108,86 @@ func (c *Command) PathString() string {
 
 // Execute should be called on the root command, it is the starting point for evaluating
 // args and routing to the requested command.
-func (c *Command) Execute(args []string) error { return runCLI(c, args) }
+func (c *Command) Execute(args []string) error { return runCLI(context.Background(), c, args) }
 
 func (c *Command) WithHelp(h string) *Command { c.help = h; return c }
 
+// WithFlagEnv allows each of the command's flags to be set from an environment
+// variable whose name is derived from the command path and flag name, see
+// FlagEnvVar. Flags set on the command line take precedence over the
+// environment, which takes precedence over the flag's default.
+func (c *Command) WithFlagEnv() *Command { c.flagEnv = true; return c }
+
+// Usage returns the usage and flags sections of c's help.
 func (c *Command) Usage() string {
-	buf := &bytes.Buffer{}
-	fs := createFlagSet(c)
-	if fs != nil {
-		fs.SetOutput(buf)
-		fs.Usage()
-		return strings.TrimSpace(buf.String())
+	width := helpWidth(c, nil)
+	sections := []string{"Usage:\n\n" + usageHelp(c, width)}
+	if flags := flagsHelp(c, width); flags != "" {
+		sections = append(sections, flags)
 	}
-	return fmt.Sprintf("Usage of %s:", c.name)
+	return strings.Join(sections, "\n\n")
 }
 
 func (c *Command) Synopsis() string {
 	buf := &bytes.Buffer{}
 	fs := createFlagSet(c)
 	if fs != nil {
+		required := map[string]bool{}
+		groups := map[string]FlagConstraint{}
+		for _, fc := range flagConstraints(c) {
+			for _, n := range fc.flags {
+				switch fc.kind {
+				case requiredConstraint:
+					required[n] = true
+				case exclusiveConstraint, oneRequiredConstraint:
+					if _, ok := groups[n]; !ok {
+						groups[n] = fc
+					}
+				}
+			}
+		}
+		done := map[string]bool{}
 		fs.VisitAll(func(f *flag.Flag) {
-			if _, ok := c.hideFlagsFromSynopsis[f.Name]; ok {
+			if _, ok := c.hideFlagsFromSynopsis[f.Name]; ok || done[f.Name] {
 				return
 			}
-			if f.DefValue == "true" || f.DefValue == "false" {
-				fmt.Fprintf(buf, "[-%s] ", f.Name)
-			} else if f.DefValue == "" {
-				fmt.Fprintf(buf, "[-%s=%s] ", f.Name, strings.ToUpper(f.Name))
+			fc, ok := groups[f.Name]
+			if !ok {
+				if required[f.Name] {
+					fmt.Fprintf(buf, "%s ", flagSynopsis(f))
+				} else {
+					fmt.Fprintf(buf, "[%s] ", flagSynopsis(f))
+				}
+				return
+			}
+			var parts []string
+			for _, n := range fc.flags {
+				if gf := fs.Lookup(n); gf != nil && !done[n] {
+					parts = append(parts, flagSynopsis(gf))
+					done[n] = true
+				}
+			}
+			if fc.kind == oneRequiredConstraint {
+				fmt.Fprintf(buf, "(%s) ", strings.Join(parts, " | "))
 			} else {
-				fmt.Fprintf(buf, "[-%s=%s (%s)] ", f.Name, strings.ToUpper(f.Name), f.DefValue)
+				fmt.Fprintf(buf, "[%s] ", strings.Join(parts, " | "))
 			}
 		})
 	}
 	if argList := makeArgList(c); argList != nil {
 		for _, a := range argList {
 			if a.required && !a.variadic {
-				fmt.Fprintf(buf, "<%s>", a.name)
+				fmt.Fprintf(buf, "<%s>", a.displayName(""))
 			} else if !a.required && !a.variadic {
-				fmt.Fprintf(buf, "[%s (%s)]", a.name, a.defaultVal)
+				fmt.Fprintf(buf, "[%s (%s)]", a.displayName(""), a.defaultVal)
 			} else if a.required && a.variadic {
 				fmt.Fprintf(buf, "<")
 				for i := 0; i < a.minVals; i++ {
-					fmt.Fprintf(buf, "%s%d, ", a.name, i)
+					fmt.Fprintf(buf, "%s, ", a.displayName(fmt.Sprint(i)))
 				}
 				fmt.Fprintf(buf, "...>")
 			} else if !a.required && a.variadic {
-				fmt.Fprintf(buf, "[%s...](%s)", a.name, strings.Join(a.defaultVals, " "))
+				fmt.Fprintf(buf, "[%s](%s)", a.displayName("..."), strings.Join(a.defaultVals, " "))
 			} else {
 				panic("logical error with arg handling; please alert the maintainers")
 			}
@@ -111,13 +196,13 @@ func (c *Command) Synopsis() string {
 	return buf.String()
 }
 
-func getSubCommand(parent *Command, name string) (*Command, bool) {
-	for _, c := range parent.Subcommands() {
-		if c.Name() == name {
-			return c, true
-		}
+func flagSynopsis(f *flag.Flag) string {
+	if f.DefValue == "true" || f.DefValue == "false" {
+		return fmt.Sprintf("-%s", f.Name)
+	} else if f.DefValue == "" {
+		return fmt.Sprintf("-%s=%s", f.Name, strings.ToUpper(f.Name))
 	}
-	return nil, false
+	return fmt.Sprintf("-%s=%s (%s)", f.Name, strings.ToUpper(f.Name), f.DefValue)
 }
 
 // RootCommand is a command that only contains subcommands and doesn't do anything
@@ -130,9 +215,10 @@ func RootCommand(name, desc string, subcommands ...*Command) *Command {
 		stdout:                os.Stdout,
 		stderr:                os.Stderr,
 		stdin:                 os.Stdin,
+		lookupEnv:             os.LookupEnv,
 		hideFlagsFromSynopsis: map[string]any{},
 	}
-	c.run = func() error {
+	c.run = func(context.Context) error {
 		return c.printHelp(c.stderr)
 	}
 	// Track commands' parents so we can generate the command's full path.
@@ -159,41 +245,69 @@ type None = *any
 // after the flag set is parsed.
 // If opts implements Env, then its ReadEnv method is called to populate it with
 // config from the environment.
+// If opts implements StdoutSetter, then its SetStdout method is called with the
+// command's stdout before the run function is called.
 // The run function is called after flags and args have been parsed, and passed
 // the resultant opts.
 func LeafCommand[T any](name, desc string, run func(opts *T) error) *Command {
+	return LeafCommandContext(name, desc, func(_ context.Context, opts *T) error {
+		return run(opts)
+	})
+}
+
+// LeafCommandContext is like LeafCommand except that the run function is also
+// passed a context, which is cancelled when the CLI receives SIGINT or SIGTERM
+// if it was started using ExecuteContext.
+func LeafCommandContext[T any](name, desc string, run func(ctx context.Context, opts *T) error) *Command {
 	opts, optionSet := makeOptionSet[T]()
 	return &Command{
 		name:                  name,
 		desc:                  desc,
 		optionSet:             optionSet,
-		run:                   func() error { return run(opts) },
+		run:                   func(ctx context.Context) error { return run(ctx, opts) },
 		stdout:                os.Stdout,
 		stderr:                os.Stderr,
 		stdin:                 os.Stdin,
+		lookupEnv:             os.LookupEnv,
 		hideFlagsFromSynopsis: map[string]any{},
 	}
 }
 
+// StdoutSetter should be implemented by options that write to the command's
+// stdout, for example to issue workflow commands.
+type StdoutSetter interface {
+	SetStdout(io.Writer)
+}
+
 type optionSet struct {
-	flags      Flags
-	flagHider  FlagHider
-	args       Args
-	argDefiner ArgDefiner
-	env        Env
-	init       Init
+	opts             any
+	flags            Flags
+	flagHider        FlagHider
+	flagConstrainer  FlagConstrainer
+	flagAliaser      FlagAliaser
+	parentOptsSetter ParentOptionsSetter
+	args             Args
+	argDefiner       ArgDefiner
+	env              Env
+	init             Init
+	stdoutSet        StdoutSetter
 }
 
 func makeOptionSet[T any]() (*T, optionSet) {
 	opts := new(T)
 	// It's ok for all/eny of flags, args, env, init to be nil.
 	var os optionSet
+	os.opts = opts
 	os.flags, _ = any(opts).(Flags)
 	os.flagHider, _ = any(opts).(FlagHider)
+	os.flagConstrainer, _ = any(opts).(FlagConstrainer)
+	os.flagAliaser, _ = any(opts).(FlagAliaser)
+	os.parentOptsSetter, _ = any(opts).(ParentOptionsSetter)
 	os.args, _ = any(opts).(Args)
 	os.argDefiner, _ = any(opts).(ArgDefiner)
 	os.env, _ = any(opts).(Env)
 	os.init, _ = any(opts).(Init)
+	os.stdoutSet, _ = any(opts).(StdoutSetter)
 
 	if os.args != nil && os.argDefiner != nil {
 		panic("opts cannot implement both Args and ArgDefiner")
@@ -202,22 +316,6 @@ func makeOptionSet[T any]() (*T, optionSet) {
 	return opts, os
 }
 
-func (c *Command) printHelp(w io.Writer) error {
-	fmt.Fprintf(w, "%s - %s\n\n", c.name, c.desc)
-	fs := createFlagSet(c)
-	if fs != nil {
-		fs.SetOutput(w)
-		fs.PrintDefaults()
-	}
-	if len(c.subs) == 0 {
-		return nil
-	}
-	fmt.Fprint(w, "Subcommands:\n\n")
-	return TabWrite(w, c.subs, func(c *Command) string {
-		return fmt.Sprintf("\t%s\t%s", c.Name(), c.Description())
-	})
-}
-
 func (c *Command) SetStdout(w io.Writer) {
 	c.stdout = w
 	for _, s := range c.subs {
@@ -238,3 +336,13 @@ func (c *Command) SetStdin(r io.Reader) {
 		s.SetStdin(r)
 	}
 }
+
+// SetLookupEnv sets the function used by c and its subcommands to look up
+// environment variables, e.g. for WithFlagEnv. Options that implement
+// EnvLookupSetter are also passed this function before ReadEnv is called.
+func (c *Command) SetLookupEnv(f func(string) (string, bool)) {
+	c.lookupEnv = f
+	for _, s := range c.subs {
+		s.SetLookupEnv(f)
+	}
+}
@@ -197,6 +197,10 @@ func TestCommand_ok(t *testing.T) {
 			args("-h"), `
 root - root command
 
+Usage:
+
+  root SUBCOMMAND
+
 Subcommands:
 
   leaf   leaf command
@@ -213,10 +217,16 @@ Subcommands:
 			args("leaf2", "-h"), `
 leaf2 - leaf command 2
 
+Usage:
+
+  root leaf2 [-flag1] [-flag2]
+
+Flags:
+
   -flag1
-        flag1 desc
+      flag1 desc
   -flag2
-        flag2 desc
+      flag2 desc
 		`,
 		},
 		{
@@ -351,3 +361,62 @@ func TestPathString(t *testing.T) {
 	}
 
 }
+
+type flagEnvOpts struct {
+	name   string
+	dryRun bool
+}
+
+func (o *flagEnvOpts) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&o.name, "name", "default", "the name")
+	fs.BoolVar(&o.dryRun, "dry-run", false, "don't do anything")
+}
+
+func TestCommand_WithFlagEnv(t *testing.T) {
+	cases := []struct {
+		desc string
+		env  map[string]string
+		args []string
+		want string
+	}{
+		{"default", nil, args("sub"), "default, false"},
+		{"env", map[string]string{"ROOT_SUB_NAME": "env", "ROOT_SUB_DRY_RUN": "true"}, args("sub"), "env, true"},
+		{"flag_overrides_env", map[string]string{"ROOT_SUB_NAME": "env"}, args("sub", "-name=flag"), "flag, false"},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			for k, v := range c.env {
+				t.Setenv(k, v)
+			}
+			var got string
+			root := RootCommand("root", "root command",
+				LeafCommand("sub", "sub command", func(o *flagEnvOpts) error {
+					got = fmt.Sprintf("%s, %t", o.name, o.dryRun)
+					return nil
+				}).WithFlagEnv(),
+			)
+			if err := root.Execute(c.args); err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("got %q; want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestCommand_WithFlagEnv_help(t *testing.T) {
+	leaf := LeafCommand("sub", "sub command", func(*flagEnvOpts) error { return nil }).WithFlagEnv()
+	root := RootCommand("root", "root command", leaf)
+	buf := &bytes.Buffer{}
+	root.SetStdout(buf)
+	if err := root.Execute(args("sub", "-h")); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"(env $ROOT_SUB_NAME)", "(env $ROOT_SUB_DRY_RUN)"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("help output missing %q:\n%s", want, buf)
+		}
+	}
+}
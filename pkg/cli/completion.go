@@ -0,0 +1,249 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CompletionShells lists the shells WriteCompletion can generate scripts for.
+var CompletionShells = []string{"bash", "zsh", "fish"}
+
+type completionOpts struct {
+	shell string
+}
+
+func (o *completionOpts) Args(al *ArgList) {
+	al.Required(&o.shell, "shell")
+}
+
+// CompletionCommand returns a command that prints a shell completion script
+// for the root of whichever command tree it's mounted in. It's intended to be
+// passed to RootCommand, where it becomes the "completion" subcommand.
+func CompletionCommand() *Command {
+	var c *Command
+	c = LeafCommand("completion", "print a shell completion script", func(o *completionOpts) error {
+		return WriteCompletion(c.stdout, c.root(), o.shell)
+	})
+	return c.WithHelp(fmt.Sprintf(`
+Prints a completion script for SHELL, which must be one of: %s.
+For example, to load bash completions in the current session:
+
+    source <(mycli completion bash)
+`, strings.Join(CompletionShells, ", ")))
+}
+
+func (c *Command) root() *Command {
+	for c.parent != nil {
+		c = c.parent
+	}
+	return c
+}
+
+// WriteCompletion writes a completion script for the command tree rooted at
+// root to w. Shell must be one of CompletionShells.
+func WriteCompletion(w io.Writer, root *Command, shell string) error {
+	nodes := completionNodes(root)
+	switch shell {
+	case "bash":
+		return writeBashCompletion(w, root.Name(), nodes)
+	case "zsh":
+		return writeZshCompletion(w, root.Name(), nodes)
+	case "fish":
+		return writeFishCompletion(w, root.Name(), nodes)
+	}
+	return fmt.Errorf("unsupported shell %q; must be one of: %s", shell, strings.Join(CompletionShells, ", "))
+}
+
+type completionItem struct {
+	name, desc string
+}
+
+// completionNode holds the completions for a single command.
+type completionNode struct {
+	path    string
+	subs    []completionItem
+	flags   []completionItem
+	hasArgs bool
+}
+
+func completionNodes(root *Command) []completionNode {
+	var nodes []completionNode
+	var walk func(c *Command)
+	walk = func(c *Command) {
+		n := completionNode{path: c.PathString()}
+		for _, s := range visibleSubcommands(c) {
+			n.subs = append(n.subs, completionItem{s.Name(), s.Description()})
+		}
+		if fs := createFlagSet(c); fs != nil {
+			fs.VisitAll(func(f *flag.Flag) {
+				if _, ok := c.hideFlagsFromSynopsis[f.Name]; ok {
+					return
+				}
+				n.flags = append(n.flags, completionItem{"-" + f.Name, f.Usage})
+			})
+		}
+		n.hasArgs = c.Args() != nil || len(makeArgList(c)) != 0
+		nodes = append(nodes, n)
+		for _, s := range visibleSubcommands(c) {
+			walk(s)
+		}
+	}
+	walk(root)
+	return nodes
+}
+
+// subcommandPaths returns the paths of all non-root commands, quoted for use
+// as a shell case pattern.
+func subcommandPaths(nodes []completionNode) string {
+	var paths []string
+	for _, n := range nodes[1:] {
+		paths = append(paths, shellQuote(n.path))
+	}
+	if len(paths) == 0 {
+		return "''"
+	}
+	return strings.Join(paths, "|")
+}
+
+func writeBashCompletion(w io.Writer, name string, nodes []completionNode) error {
+	fn := "_" + shellIdent(name) + "_completions"
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "# bash completion for %s\n\n", name)
+	fmt.Fprintf(b, "%s() {\n", fn)
+	fmt.Fprintf(b, "    local cur word i cmdpath=%s\n", shellQuote(name))
+	b.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	b.WriteString("        word=\"${COMP_WORDS[i]}\"\n")
+	b.WriteString("        case \"$cmdpath $word\" in\n")
+	fmt.Fprintf(b, "            %s) cmdpath=\"$cmdpath $word\" ;;\n", subcommandPaths(nodes))
+	b.WriteString("        esac\n")
+	b.WriteString("    done\n")
+	b.WriteString("    case \"$cmdpath\" in\n")
+	for _, n := range nodes {
+		var words []string
+		for _, s := range n.subs {
+			words = append(words, s.name)
+		}
+		for _, f := range n.flags {
+			words = append(words, f.name)
+		}
+		fmt.Fprintf(b, "        %s)\n", shellQuote(n.path))
+		fmt.Fprintf(b, "            COMPREPLY=($(compgen -W %s -- \"$cur\"))\n", shellQuote(strings.Join(words, " ")))
+		if n.hasArgs {
+			b.WriteString("            COMPREPLY+=($(compgen -f -- \"$cur\"))\n")
+		}
+		b.WriteString("            ;;\n")
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("}\n\n")
+	fmt.Fprintf(b, "complete -o filenames -F %s %s\n", fn, name)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeZshCompletion(w io.Writer, name string, nodes []completionNode) error {
+	fn := "_" + shellIdent(name)
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "#compdef %s\n\n", name)
+	fmt.Fprintf(b, "%s() {\n", fn)
+	fmt.Fprintf(b, "    local word i cmdpath=%s\n", shellQuote(name))
+	b.WriteString("    local -a subcmds flags\n")
+	b.WriteString("    for ((i = 2; i < CURRENT; i++)); do\n")
+	b.WriteString("        word=\"${words[i]}\"\n")
+	b.WriteString("        case \"$cmdpath $word\" in\n")
+	fmt.Fprintf(b, "            %s) cmdpath=\"$cmdpath $word\" ;;\n", subcommandPaths(nodes))
+	b.WriteString("        esac\n")
+	b.WriteString("    done\n")
+	b.WriteString("    case \"$cmdpath\" in\n")
+	for _, n := range nodes {
+		fmt.Fprintf(b, "        %s)\n", shellQuote(n.path))
+		fmt.Fprintf(b, "            subcmds=(%s)\n", zshDescribeItems(n.subs))
+		fmt.Fprintf(b, "            flags=(%s)\n", zshDescribeItems(n.flags))
+		if n.hasArgs {
+			b.WriteString("            _files\n")
+		}
+		b.WriteString("            ;;\n")
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("    _describe -t commands 'command' subcmds\n")
+	b.WriteString("    _describe -t flags 'flag' flags\n")
+	b.WriteString("}\n\n")
+	fmt.Fprintf(b, "compdef %s %s\n", fn, name)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func zshDescribeItems(items []completionItem) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		name := strings.ReplaceAll(item.name, ":", `\:`)
+		quoted[i] = shellQuote(name + ":" + firstLine(item.desc))
+	}
+	return strings.Join(quoted, " ")
+}
+
+func writeFishCompletion(w io.Writer, name string, nodes []completionNode) error {
+	fn := "__" + shellIdent(name) + "_cmdpath"
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "# fish completion for %s\n\n", name)
+	fmt.Fprintf(b, "function %s\n", fn)
+	fmt.Fprintf(b, "    set -l cmdpath %s\n", fishQuote(name))
+	b.WriteString("    for word in (commandline -opc)[2..-1]\n")
+	b.WriteString("        switch \"$cmdpath $word\"\n")
+	paths := []string{"''"}
+	for _, n := range nodes[1:] {
+		paths = append(paths, fishQuote(n.path))
+	}
+	fmt.Fprintf(b, "            case %s\n", strings.Join(paths[min(1, len(paths)-1):], " "))
+	b.WriteString("                set cmdpath \"$cmdpath $word\"\n")
+	b.WriteString("        end\n")
+	b.WriteString("    end\n")
+	b.WriteString("    echo $cmdpath\n")
+	b.WriteString("end\n\n")
+	fmt.Fprintf(b, "complete -c %s -f\n", name)
+	for _, n := range nodes {
+		cond := fishQuote(fmt.Sprintf("test (%s) = %s", fn, fishQuote(n.path)))
+		for _, s := range n.subs {
+			fmt.Fprintf(b, "complete -c %s -n %s -a %s -d %s\n", name, cond, fishQuote(s.name), fishQuote(firstLine(s.desc)))
+		}
+		for _, f := range n.flags {
+			fmt.Fprintf(b, "complete -c %s -n %s -o %s -d %s\n", name, cond, fishQuote(f.name[1:]), fishQuote(firstLine(f.desc)))
+		}
+		if n.hasArgs {
+			fmt.Fprintf(b, "complete -c %s -n %s -F\n", name, cond)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// shellQuote single-quotes s so it's treated literally by bash and zsh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// fishQuote single-quotes s so it's treated literally by fish, which, unlike
+// bash and zsh, supports backslash escapes inside single quotes.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+// shellIdent replaces any characters in s not valid in a shell function name.
+func shellIdent(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
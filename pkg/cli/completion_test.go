@@ -0,0 +1,32 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/composite-action-framework-go/pkg/testhelpers/goldenfile"
+)
+
+func TestWriteCompletion(t *testing.T) {
+	for _, shell := range CompletionShells {
+		shell := shell
+		t.Run(shell, func(t *testing.T) {
+			root, _ := testCLI()
+			goldenfile.Do(t, func(got *os.File) {
+				if err := WriteCompletion(got, root, shell); err != nil {
+					t.Fatal(err)
+				}
+			})
+		})
+	}
+}
+
+func TestCompletionCommand(t *testing.T) {
+	root := RootCommand("root", "root command", CompletionCommand())
+	if err := root.Execute(args("completion", "powershell")); err == nil {
+		t.Fatal("got nil error for unsupported shell")
+	}
+}
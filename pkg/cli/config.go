@@ -0,0 +1,158 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/hashicorp/composite-action-framework-go/pkg/json"
+	"gopkg.in/yaml.v3"
+)
+
+// WithConfigFile adds a flag called flagName to the command, which names a
+// config file to read the command's flag values from. If envVar isn't empty,
+// the file may instead be named by that environment variable.
+//
+// The file's format is determined by its extension, which must be one of .json,
+// .yaml, .yml or .toml. It must contain a single object whose keys are flag
+// names and whose values are scalars, or lists of scalars for flags that may be
+// repeated, i.e. those whose flag.Value is a slice type. Keys that don't name
+// one of the command's flags are an error.
+//
+// Flag values are taken from the first of these that sets them:
+//
+//  1. The command line.
+//  2. The flag's environment variable, see WithFlagEnv.
+//  3. The config file.
+//  4. The flag's default.
+func (c *Command) WithConfigFile(flagName, envVar string) *Command {
+	c.configFlag, c.configEnv = flagName, envVar
+	return c
+}
+
+func registerConfigFlag(c *Command, fs *flag.FlagSet) {
+	usage := "path to a config file (JSON, YAML or TOML) containing flag values"
+	if c.configEnv != "" {
+		usage = fmt.Sprintf("%s (env $%s)", usage, c.configEnv)
+	}
+	fs.StringVar(&c.configPath, c.configFlag, "", usage)
+}
+
+// setFlagsFromConfig sets any flags not already in set, i.e. those not set on
+// the command line or from the environment, from the command's config file, if
+// there is one.
+func setFlagsFromConfig(c *Command, set map[string]bool) error {
+	path := c.configPath
+	if path == "" && c.configEnv != "" {
+		path, _ = c.LookupEnv(c.configEnv)
+	}
+	if path == "" {
+		return nil
+	}
+	config, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	var unknown []string
+	for key := range config {
+		if key == c.configFlag || c.flagSet.Lookup(key) == nil {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) != 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("config file %s: unknown keys: %s", path, strings.Join(unknown, ", "))
+	}
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if set[key] {
+			continue
+		}
+		if err := setConfigValue(c.flagSet, key, config[key]); err != nil {
+			return fmt.Errorf("config file %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func setConfigValue(fs *flag.FlagSet, key string, value any) error {
+	values, ok := value.([]any)
+	if !ok {
+		values = []any{value}
+	} else if !isListFlag(fs.Lookup(key)) {
+		return fmt.Errorf("key %s: got a list, but -%s takes a single value", key, key)
+	}
+	for _, v := range values {
+		var s string
+		switch v := v.(type) {
+		case string, bool, int, int64, uint64:
+			s = fmt.Sprint(v)
+		case float64:
+			// JSON numbers are all float64s, and fmt would write large ones in
+			// exponent form, which integer flags can't parse.
+			s = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			return fmt.Errorf("key %s: unsupported value type %T", key, v)
+		}
+		if err := fs.Set(key, s); err != nil {
+			return fmt.Errorf("invalid value %q for key %s: %w", s, key, err)
+		}
+	}
+	return nil
+}
+
+// isListFlag reports whether f may be given a list of values in a config file,
+// which is the case if its flag.Value is a slice, like a flag that appends
+// each value it's set to.
+func isListFlag(f *flag.Flag) bool {
+	value := f.Value
+	if v, ok := value.(*recordingValue); ok {
+		value = v.Value
+	}
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Slice
+}
+
+func readConfigFile(path string) (map[string]any, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".json" {
+		config, err := json.ReadFile[map[string]any](path)
+		if err != nil {
+			return nil, fmt.Errorf("config file %s: %w", path, err)
+		}
+		return config, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := map[string]any{}
+	switch ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &config)
+	case ".toml":
+		err = toml.Unmarshal(b, &config)
+	default:
+		return nil, fmt.Errorf("config file %s: unsupported format %q; must be one of .json, .yaml, .yml or .toml", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return config, nil
+}
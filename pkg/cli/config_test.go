@@ -0,0 +1,110 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type configOpts struct {
+	name  string
+	count int
+	debug bool
+	tags  listFlag
+}
+
+type listFlag []string
+
+func (l *listFlag) String() string     { return strings.Join(*l, ",") }
+func (l *listFlag) Set(s string) error { *l = append(*l, s); return nil }
+
+func (o *configOpts) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&o.name, "name", "default", "name")
+	fs.IntVar(&o.count, "count", 0, "count")
+	fs.BoolVar(&o.debug, "debug", false, "debug")
+	fs.Var(&o.tags, "tag", "tag (repeatable)")
+}
+
+func TestWithConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"config.json": `{"name": "json", "count": 3, "debug": true, "tag": ["a", "b"]}`,
+		"config.yaml": "name: yaml\ncount: 4\ntag:\n  - c\n",
+		"config.toml": "name = \"toml\"\ncount = 5\ndebug = true\n",
+		"unknown.yml": "name: x\nnope: 1\nalso: 2\n",
+		"bad.json":    `{"count": "three"}`,
+		"nested.yaml": "name:\n  x: y\n",
+		"config.ini":  "name=ini\n",
+		"list.json":   `{"name": ["a", "b"]}`,
+		"large.json":  `{"count": 1000000, "name": 2.5}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	cases := []struct {
+		desc    string
+		args    []string
+		env     map[string]string
+		want    string
+		wantErr string
+	}{
+		{"no config", args("leaf"), nil, "default 0 false []", ""},
+		{"json", args("leaf", "-config", path("config.json")), nil, "json 3 true [a b]", ""},
+		{"yaml", args("leaf", "-config", path("config.yaml")), nil, "yaml 4 false [c]", ""},
+		{"toml", args("leaf", "-config", path("config.toml")), nil, "toml 5 true []", ""},
+		{"config env", args("leaf"), map[string]string{"ROOT_CONFIG": path("config.toml")}, "toml 5 true []", ""},
+		{"flags override config", args("leaf", "-config", path("config.json"), "-name", "flag"), nil, "flag 3 true [a b]", ""},
+		{"flag env overrides config", args("leaf", "-config", path("config.yaml")), map[string]string{"ROOT_LEAF_COUNT": "9"}, "yaml 9 false [c]", ""},
+		{"flag env overrides config list", args("leaf", "-config", path("config.json")), map[string]string{"ROOT_LEAF_TAG": "env"}, "json 3 true [env]", ""},
+		{"flags override flag env", args("leaf", "-config", path("config.yaml"), "-count", "1"), map[string]string{"ROOT_LEAF_COUNT": "9"}, "yaml 1 false [c]", ""},
+		{"large json number", args("leaf", "-config", path("large.json")), nil, "2.5 1000000 false []", ""},
+		{"list for scalar flag", args("leaf", "-config", path("list.json")), nil, "",
+			fmt.Sprintf("config file %s: key name: got a list, but -name takes a single value", path("list.json"))},
+		{"unknown keys", args("leaf", "-config", path("unknown.yml")), nil, "",
+			fmt.Sprintf("config file %s: unknown keys: also, nope", path("unknown.yml"))},
+		{"invalid value", args("leaf", "-config", path("bad.json")), nil, "",
+			fmt.Sprintf(`config file %s: invalid value "three" for key count: parse error`, path("bad.json"))},
+		{"nested value", args("leaf", "-config", path("nested.yaml")), nil, "",
+			fmt.Sprintf("config file %s: key name: unsupported value type map[string]interface {}", path("nested.yaml"))},
+		{"unsupported format", args("leaf", "-config", path("config.ini")), nil, "",
+			fmt.Sprintf(`config file %s: unsupported format ".ini"; must be one of .json, .yaml, .yml or .toml`, path("config.ini"))},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			for k, v := range c.env {
+				t.Setenv(k, v)
+			}
+			var got string
+			root := RootCommand("root", "root command",
+				LeafCommand("leaf", "leaf command", func(o *configOpts) error {
+					got = fmt.Sprintf("%s %d %t %v", o.name, o.count, o.debug, []string(o.tags))
+					return nil
+				}).WithConfigFile("config", "ROOT_CONFIG").WithFlagEnv(),
+			)
+			err := root.Execute(c.args)
+			if c.wantErr != "" {
+				if err == nil || err.Error() != c.wantErr {
+					t.Fatalf("got error %v; want %q", err, c.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("got %q; want %q", got, c.want)
+			}
+		})
+	}
+}
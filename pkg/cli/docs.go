@@ -0,0 +1,141 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/composite-action-framework-go/pkg/fs"
+)
+
+// MarkdownFileName returns the name of the markdown reference page for c,
+// which is its path joined with underscores.
+func MarkdownFileName(c *Command) string {
+	return strings.Join(c.Path(), "_") + ".md"
+}
+
+// WriteMarkdownDocs writes a markdown reference page for every command in the
+// tree rooted at root to dir, using MarkdownFileName to name each page.
+func WriteMarkdownDocs(dir string, root *Command) error {
+	buf := &bytes.Buffer{}
+	if err := WriteMarkdown(buf, root); err != nil {
+		return err
+	}
+	if err := fs.WriteFile(filepath.Join(dir, MarkdownFileName(root)), buf.Bytes()); err != nil {
+		return err
+	}
+	for _, s := range visibleSubcommands(root) {
+		if err := WriteMarkdownDocs(dir, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteMarkdown writes a markdown reference page for the single command c to w.
+func WriteMarkdown(w io.Writer, c *Command) error {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "# %s\n\n", c.PathString())
+	fmt.Fprintf(b, "%s\n\n", c.Description())
+	if notice := stateNotice(c); notice != "" {
+		fmt.Fprintf(b, "> %s\n\n", notice)
+	}
+	if len(c.aliases) != 0 {
+		fmt.Fprintf(b, "Aliases: %s\n\n", strings.Join(c.aliases, ", "))
+	}
+	fmt.Fprintf(b, "## Synopsis\n\n```\n%s\n```\n\n", strings.TrimSpace(c.PathString()+" "+c.Synopsis()))
+	if help := strings.TrimSpace(c.help); help != "" {
+		fmt.Fprintf(b, "%s\n\n", help)
+	}
+	if fs := createFlagSet(c); fs != nil {
+		b.WriteString("## Flags\n\n")
+		b.WriteString("| Flag | Default | Description |\n| --- | --- | --- |\n")
+		fs.VisitAll(func(f *flag.Flag) {
+			fmt.Fprintf(b, "| `-%s` | %s | %s |\n", f.Name, markdownCode(f.DefValue), markdownCell(f.Usage))
+		})
+		b.WriteString("\n")
+	}
+	if argList := makeArgList(c); len(argList) != 0 {
+		b.WriteString("## Arguments\n\n")
+		b.WriteString("| Argument | Required | Default |\n| --- | --- | --- |\n")
+		for _, a := range argList {
+			name, def := a.displayName(""), a.defaultVal
+			if a.variadic {
+				name = a.displayName("...")
+				def = strings.Join(a.defaultVals, " ")
+			}
+			fmt.Fprintf(b, "| %s | %t | %s |\n", markdownCode(name), a.required, markdownCode(def))
+		}
+		b.WriteString("\n")
+	}
+	if len(c.examples) != 0 {
+		b.WriteString("## Examples\n\n")
+		for _, e := range c.examples {
+			fmt.Fprintf(b, "%s\n\n```\n%s %s\n```\n\n", e.Description, c.PathString(), e.Args)
+		}
+	}
+	if subs := visibleSubcommands(c); len(subs) != 0 {
+		b.WriteString("## Subcommands\n\n")
+		for _, s := range subs {
+			fmt.Fprintf(b, "- [%s](%s) - %s%s\n", s.Name(), MarkdownFileName(s), stateLabel(s), s.Description())
+		}
+		b.WriteString("\n")
+	}
+	if c.parent != nil {
+		fmt.Fprintf(b, "## See also\n\n- [%s](%s) - %s\n\n", c.parent.PathString(), MarkdownFileName(c.parent), c.parent.Description())
+	}
+	_, err := io.WriteString(w, strings.TrimRight(b.String(), "\n")+"\n")
+	return err
+}
+
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + markdownCell(s) + "`"
+}
+
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", "<br>").Replace(s)
+}
+
+// WriteActionInputs writes an action.yml inputs: skeleton to w, with one input
+// for each of c's flags and args. Flag inputs are named after the flag, and
+// arg inputs after the lower-cased arg name.
+func WriteActionInputs(w io.Writer, c *Command) error {
+	b := &strings.Builder{}
+	writeInput := func(name, desc string, required bool, def string) {
+		fmt.Fprintf(b, "  %s:\n", name)
+		fmt.Fprintf(b, "    description: %s\n", strconv.Quote(desc))
+		fmt.Fprintf(b, "    required: %t\n", required)
+		if def != "" {
+			fmt.Fprintf(b, "    default: %s\n", strconv.Quote(def))
+		}
+	}
+	if fs := createFlagSet(c); fs != nil {
+		fs.VisitAll(func(f *flag.Flag) {
+			writeInput(f.Name, f.Usage, false, f.DefValue)
+		})
+	}
+	for _, a := range makeArgList(c) {
+		def := a.defaultVal
+		if a.variadic {
+			def = strings.Join(a.defaultVals, "\n")
+		}
+		desc := fmt.Sprintf("%s argument", a.name)
+		writeInput(strings.ToLower(a.name), desc, a.required, def)
+	}
+	if b.Len() == 0 {
+		_, err := io.WriteString(w, "inputs: {}\n")
+		return err
+	}
+	_, err := io.WriteString(w, "inputs:\n"+b.String())
+	return err
+}
@@ -0,0 +1,98 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/composite-action-framework-go/pkg/testhelpers/goldenfile"
+)
+
+type docsOpts struct {
+	testFlags
+	name  string
+	mode  string
+	paths []string
+}
+
+func (o *docsOpts) Flags(fs *flag.FlagSet) {
+	o.testFlags.Flags(fs)
+	fs.StringVar(&o.name, "name", "default | name", "the name\nto use")
+}
+
+func (o *docsOpts) Args(al *ArgList) {
+	RequiredArg(al, &o.mode, "mode", EnumParser("fast", "slow"))
+	al.OptionalVariadic(&o.paths, "paths", ".")
+}
+
+func docsCLI() (root, leaf *Command) {
+	leaf = LeafCommand("leaf", "leaf command", func(*docsOpts) error { return nil }).
+		WithHelp("Leaf does things.")
+	root = RootCommand("root", "root command", leaf)
+	return root, leaf
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	root, leaf := docsCLI()
+	for _, c := range []*Command{root, leaf} {
+		c := c
+		t.Run(c.Name(), func(t *testing.T) {
+			goldenfile.Do(t, func(got *os.File) {
+				if err := WriteMarkdown(got, c); err != nil {
+					t.Fatal(err)
+				}
+			})
+		})
+	}
+}
+
+func TestWriteMarkdownDocs(t *testing.T) {
+	_, leaf := docsCLI()
+	hidden := LeafCommand("hidden", "hidden command", func(None) error { return nil }).WithHidden()
+	root := RootCommand("root", "root command",
+		RootCommand("sub", "sub command", leaf),
+		hidden,
+	)
+	dir := t.TempDir()
+	if err := WriteMarkdownDocs(dir, root); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if want := []string{"root.md", "root_sub.md", "root_sub_leaf.md"}; !slices.Equal(names, want) {
+		t.Fatalf("got files %q; want %q", names, want)
+	}
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			b, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			goldenfile.Do(t, func(got *os.File) {
+				if _, err := got.Write(b); err != nil {
+					t.Fatal(err)
+				}
+			})
+		})
+	}
+}
+
+func TestWriteActionInputs(t *testing.T) {
+	_, leaf := docsCLI()
+	goldenfile.Do(t, func(got *os.File) {
+		if err := WriteActionInputs(got, leaf); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -19,10 +19,24 @@ func ReadEnvAll(objs ...Env) error {
 	return nil
 }
 
+// EnvLookupSetter can be implemented by options that implement Env, to read
+// environment variables using the command's LookupEnv function rather than
+// directly from the process environment. This allows tests to run commands
+// with an isolated environment, see Command.SetLookupEnv.
+type EnvLookupSetter interface {
+	SetLookupEnv(func(name string) (string, bool))
+}
+
 func parseEnv(c *Command) error {
-	e := c.Env()
-	if e == nil {
-		return nil
+	return readEnv(c, c.Env())
+}
+
+func readEnv(c *Command, opts any) error {
+	if s, ok := opts.(EnvLookupSetter); ok {
+		s.SetLookupEnv(c.lookupEnv)
+	}
+	if e, ok := opts.(Env); ok {
+		return e.ReadEnv()
 	}
-	return e.ReadEnv()
+	return nil
 }
@@ -3,9 +3,173 @@
 
 package cli
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 var (
 	ErrNotImplemented = errors.New("not implemented")
 	ErrNoArgsAllowed  = errors.New("no args allowed")
 )
+
+// Exit codes used by Main.
+const (
+	ExitCodeOK      = 0
+	ExitCodeRuntime = 1
+	ExitCodeUsage   = 2
+)
+
+// ExitCoder is implemented by errors that determine the process exit code
+// when returned from Execute and handled by Main.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// ExitCode returns the exit code for err. This is ExitCodeOK for nil errors,
+// the exit code of the first error in err's chain that implements ExitCoder,
+// or else ExitCodeRuntime.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+	var ec ExitCoder
+	if errors.As(err, &ec) {
+		return ec.ExitCode()
+	}
+	return ExitCodeRuntime
+}
+
+// UsageError means that a command was invoked incorrectly, e.g. with an unknown
+// flag or subcommand, or missing args. It wraps the more specific error.
+type UsageError struct {
+	// Command is the command whose flags or args were invalid.
+	Command *Command
+	Err     error
+}
+
+func (e *UsageError) Error() string { return e.Err.Error() }
+func (e *UsageError) Unwrap() error { return e.Err }
+func (e *UsageError) ExitCode() int { return ExitCodeUsage }
+
+func usageError(c *Command, err error) error {
+	if err == nil {
+		return nil
+	}
+	var ue *UsageError
+	if errors.As(err, &ue) {
+		return err
+	}
+	return &UsageError{Command: c, Err: err}
+}
+
+// SubcommandNotFoundError is wrapped in a UsageError when a subcommand named
+// on the command line doesn't exist.
+type SubcommandNotFoundError struct {
+	Name string
+	// Suggestions are the names of subcommands similar to Name, or matching it
+	// ambiguously when prefix matching is enabled.
+	Suggestions []string
+	// Available are the names of all subcommands.
+	Available []string
+}
+
+func (e *SubcommandNotFoundError) Error() string {
+	msg := fmt.Sprintf("subcommand %q not found", e.Name)
+	if len(e.Suggestions) != 0 {
+		msg += fmt.Sprintf("; did you mean %s?", quotedList(e.Suggestions, "or"))
+	}
+	if len(e.Available) != 0 {
+		msg += fmt.Sprintf(" (available: %s)", strings.Join(e.Available, ", "))
+	}
+	return msg
+}
+
+// quotedList returns a list of quoted items for use in messages,
+// e.g. "a", "b" or "c".
+func quotedList(items []string, conj string) string {
+	q := make([]string, len(items))
+	for i, item := range items {
+		q[i] = fmt.Sprintf("%q", item)
+	}
+	if len(q) == 1 {
+		return q[0]
+	}
+	return strings.Join(q[:len(q)-1], ", ") + " " + conj + " " + q[len(q)-1]
+}
+
+// MissingArgError is wrapped in a UsageError when a required arg isn't supplied.
+type MissingArgError struct {
+	Name string
+	// Min is the minimum number of values for variadic args, or zero.
+	Min int
+}
+
+func (e *MissingArgError) Error() string {
+	if e.Min == 0 {
+		return fmt.Sprintf("required argument missing: %s", e.Name)
+	}
+	return fmt.Sprintf("required %s argument(s) missing; you must supply at least %d", e.Name, e.Min)
+}
+
+// InvalidArgError is wrapped in a UsageError when an arg value can't be parsed
+// or fails validation.
+type InvalidArgError struct {
+	Name  string
+	Value string
+	Err   error
+}
+
+func (e *InvalidArgError) Error() string {
+	return fmt.Sprintf("invalid value %q for argument %s: %s", e.Value, e.Name, e.Err)
+}
+
+func (e *InvalidArgError) Unwrap() error { return e.Err }
+
+// RuntimeError wraps errors returned while running a command, i.e. from its
+// ReadEnv, Init or run function.
+type RuntimeError struct {
+	Err error
+}
+
+func (e *RuntimeError) Error() string { return e.Err.Error() }
+func (e *RuntimeError) Unwrap() error { return e.Err }
+
+// ExitCode returns the exit code of the wrapped error if it implements
+// ExitCoder, or else ExitCodeRuntime.
+func (e *RuntimeError) ExitCode() int {
+	var ec ExitCoder
+	if errors.As(e.Err, &ec) {
+		return ec.ExitCode()
+	}
+	return ExitCodeRuntime
+}
+
+func runtimeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var re *RuntimeError
+	if errors.As(err, &re) {
+		return err
+	}
+	return &RuntimeError{Err: err}
+}
+
+type exitCodeError struct {
+	err  error
+	code int
+}
+
+func (e *exitCodeError) Error() string { return e.err.Error() }
+func (e *exitCodeError) Unwrap() error { return e.err }
+func (e *exitCodeError) ExitCode() int { return e.code }
+
+// WithExitCode wraps err so that Main exits with code when it's returned.
+func WithExitCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	return &exitCodeError{err: err, code: code}
+}
@@ -0,0 +1,104 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type requiredArgOpts struct {
+	name string
+}
+
+func (o *requiredArgOpts) Args(al *ArgList) {
+	al.Required(&o.name, "name")
+}
+
+func TestMainCode(t *testing.T) {
+	errBoom := errors.New("boom")
+	cases := []struct {
+		desc       string
+		args       []string
+		wantCode   int
+		wantStdout string // Prefix of stdout.
+		wantStderr string
+	}{
+		{"ok", args("ok"), ExitCodeOK, "", ""},
+		{"help", args("flags", "-flag1", "-h"), ExitCodeOK, "flags - flags\n", ""},
+		{"runtime", args("fail"), ExitCodeRuntime, "", "Error: boom\n"},
+		{"custom_code", args("custom"), 3, "", "Error: boom\n"},
+		{
+			"subcommand_not_found",
+			args("nope"),
+			ExitCodeUsage,
+			"",
+			`Error: subcommand "nope" not found (available: ok, fail, custom, args, flags)
+Usage: root SUBCOMMAND
+Run 'root -h' for help.
+`,
+		},
+		{
+			"missing_arg",
+			args("args"),
+			ExitCodeUsage,
+			"",
+			`Error: required argument missing: NAME
+Usage: root args <NAME>
+Run 'root args -h' for help.
+`,
+		},
+		{
+			"bad_flag",
+			args("flags", "-nope"),
+			ExitCodeUsage,
+			"",
+			`Error: flag provided but not defined: -nope
+Usage: root flags [-flag1] [-flag2]
+Run 'root flags -h' for help.
+`,
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			root := RootCommand("root", "root command",
+				LeafCommand("ok", "ok", func(None) error { return nil }),
+				LeafCommand("fail", "fail", func(None) error { return errBoom }),
+				LeafCommand("custom", "custom", func(None) error { return WithExitCode(errBoom, 3) }),
+				LeafCommand("args", "args", func(*requiredArgOpts) error { return nil }),
+				LeafCommand("flags", "flags", func(*testFlags) error { return nil }),
+			)
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			root.SetStdout(stdout)
+			root.SetStderr(stderr)
+			if got := MainCode(root, c.args); got != c.wantCode {
+				t.Errorf("got exit code %d; want %d", got, c.wantCode)
+			}
+			if got := stdout.String(); !strings.HasPrefix(got, c.wantStdout) {
+				t.Errorf("got stdout:\n%s\nwant it to start with:\n%s", got, c.wantStdout)
+			}
+			if got := stderr.String(); got != c.wantStderr {
+				t.Errorf("got stderr:\n%s\nwant:\n%s", got, c.wantStderr)
+			}
+		})
+	}
+}
+
+func TestExecute_errorTypes(t *testing.T) {
+	root := RootCommand("root", "root command",
+		LeafCommand("args", "args", func(*requiredArgOpts) error { return nil }),
+	)
+	err := root.Execute(args("args"))
+	var ue *UsageError
+	if !errors.As(err, &ue) || ue.Command.Name() != "args" {
+		t.Fatalf("got %#v; want *UsageError for args command", err)
+	}
+	var mae *MissingArgError
+	if !errors.As(err, &mae) || mae.Name != "NAME" {
+		t.Fatalf("got %#v; want *MissingArgError for NAME", err)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// FlagConstrainer can be implemented by options that implement Flags, to declare
+// constraints on which of those flags must or must not be set together.
+// Constraints are checked after flags are parsed and before Init is called.
+type FlagConstrainer interface {
+	FlagConstraints() []FlagConstraint
+}
+
+type constraintKind int
+
+const (
+	requiredConstraint constraintKind = iota
+	exclusiveConstraint
+	oneRequiredConstraint
+	requiresConstraint
+)
+
+// FlagConstraint is a constraint on a set of flags. Create one using
+// RequiredFlags, MutuallyExclusiveFlags, OneRequiredFlag or FlagRequires.
+type FlagConstraint struct {
+	kind  constraintKind
+	flags []string
+}
+
+// RequiredFlags means that each of the named flags must be set.
+func RequiredFlags(names ...string) FlagConstraint {
+	return FlagConstraint{requiredConstraint, names}
+}
+
+// MutuallyExclusiveFlags means that at most one of the named flags may be set.
+func MutuallyExclusiveFlags(names ...string) FlagConstraint {
+	return FlagConstraint{exclusiveConstraint, names}
+}
+
+// OneRequiredFlag means that at least one of the named flags must be set.
+func OneRequiredFlag(names ...string) FlagConstraint {
+	return FlagConstraint{oneRequiredConstraint, names}
+}
+
+// FlagRequires means that if the flag called name is set, then all of the
+// flags named in others must also be set.
+func FlagRequires(name string, others ...string) FlagConstraint {
+	return FlagConstraint{requiresConstraint, append([]string{name}, others...)}
+}
+
+func dashed(names []string) string {
+	d := make([]string, len(names))
+	for i, n := range names {
+		d[i] = "-" + n
+	}
+	return strings.Join(d, ", ")
+}
+
+// String describes the constraint for use in help output.
+func (fc FlagConstraint) String() string {
+	switch fc.kind {
+	case requiredConstraint:
+		if len(fc.flags) == 1 {
+			return fmt.Sprintf("-%s is required", fc.flags[0])
+		}
+		return fmt.Sprintf("%s are required", dashed(fc.flags))
+	case exclusiveConstraint:
+		return fmt.Sprintf("only one of %s may be set", dashed(fc.flags))
+	case oneRequiredConstraint:
+		return fmt.Sprintf("one of %s is required", dashed(fc.flags))
+	case requiresConstraint:
+		return fmt.Sprintf("-%s requires %s", fc.flags[0], dashed(fc.flags[1:]))
+	}
+	panic("logical error with flag constraints; please alert the maintainers")
+}
+
+func (fc FlagConstraint) check(set map[string]bool) error {
+	var got []string
+	for _, n := range fc.flags {
+		if set[n] {
+			got = append(got, n)
+		}
+	}
+	switch fc.kind {
+	case requiredConstraint:
+		for _, n := range fc.flags {
+			if !set[n] {
+				return fmt.Errorf("required flag -%s not set", n)
+			}
+		}
+	case exclusiveConstraint:
+		if len(got) > 1 {
+			return fmt.Errorf("flags %s cannot be used together", dashed(got))
+		}
+	case oneRequiredConstraint:
+		if len(got) == 0 {
+			return fmt.Errorf("one of the flags %s must be set", dashed(fc.flags))
+		}
+	case requiresConstraint:
+		if !set[fc.flags[0]] {
+			return nil
+		}
+		for _, n := range fc.flags[1:] {
+			if !set[n] {
+				return fmt.Errorf("flag -%s requires -%s to be set", fc.flags[0], n)
+			}
+		}
+	}
+	return nil
+}
+
+func flagConstraints(c *Command) []FlagConstraint {
+	if c.flagConstrainer == nil {
+		return nil
+	}
+	return c.flagConstrainer.FlagConstraints()
+}
+
+// checkFlagConstraints checks the command's flag constraints against the flags
+// set on the command line (or from the environment, see WithFlagEnv).
+func checkFlagConstraints(c *Command) error {
+	constraints := flagConstraints(c)
+	if len(constraints) == 0 {
+		return nil
+	}
+	set := map[string]bool{}
+	c.flagSet.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	for _, fc := range constraints {
+		for _, n := range fc.flags {
+			if c.flagSet.Lookup(n) == nil {
+				panic(fmt.Sprintf("flag constraint %q refers to undefined flag -%s", fc, n))
+			}
+		}
+		if err := fc.check(set); err != nil {
+			return err
+		}
+	}
+	return nil
+}
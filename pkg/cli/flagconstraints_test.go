@@ -0,0 +1,98 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+)
+
+type constrainedOpts struct {
+	name, token, tokenFile, user, password string
+	json, yaml                             bool
+}
+
+func (o *constrainedOpts) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&o.name, "name", "", "the name")
+	fs.StringVar(&o.token, "token", "", "auth token")
+	fs.StringVar(&o.tokenFile, "token-file", "", "auth token file")
+	fs.StringVar(&o.user, "user", "", "user name")
+	fs.StringVar(&o.password, "password", "", "password")
+	fs.BoolVar(&o.json, "json", false, "output json")
+	fs.BoolVar(&o.yaml, "yaml", false, "output yaml")
+}
+
+func (o *constrainedOpts) FlagConstraints() []FlagConstraint {
+	return []FlagConstraint{
+		RequiredFlags("name"),
+		OneRequiredFlag("token", "token-file"),
+		FlagRequires("user", "password"),
+		MutuallyExclusiveFlags("json", "yaml"),
+	}
+}
+
+func constrainedCLI() *Command {
+	return RootCommand("root", "root command",
+		LeafCommand("leaf", "leaf command", func(*constrainedOpts) error { return nil }),
+	)
+}
+
+func TestFlagConstraints(t *testing.T) {
+	cases := []struct {
+		args    []string
+		wantErr string
+	}{
+		{args("leaf", "-name=a", "-token=t"), ""},
+		{args("leaf", "-name=a", "-token-file=f", "-user=u", "-password=p", "-yaml"), ""},
+		{args("leaf", "-token=t"), "required flag -name not set"},
+		{args("leaf", "-name=a"), "one of the flags -token, -token-file must be set"},
+		{args("leaf", "-name=a", "-token=t", "-user=u"), "flag -user requires -password to be set"},
+		{args("leaf", "-name=a", "-token=t", "-json", "-yaml"), "flags -json, -yaml cannot be used together"},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(strings.Join(c.args[2:], " "), func(t *testing.T) {
+			err := constrainedCLI().Execute(c.args)
+			if c.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || err.Error() != c.wantErr {
+				t.Fatalf("got error %v; want %q", err, c.wantErr)
+			}
+			if ExitCode(err) != ExitCodeUsage {
+				t.Errorf("got exit code %d; want %d", ExitCode(err), ExitCodeUsage)
+			}
+		})
+	}
+}
+
+func TestFlagConstraints_synopsisAndHelp(t *testing.T) {
+	root := constrainedCLI()
+	leaf := root.Subcommands()[0]
+	want := "[-json | -yaml] -name=NAME [-password=PASSWORD] (-token=TOKEN | -token-file=TOKEN-FILE) [-user=USER] "
+	if got := leaf.Synopsis(); got != want {
+		t.Errorf("got synopsis:\n%q\nwant:\n%q", got, want)
+	}
+	buf := &bytes.Buffer{}
+	root.SetStdout(buf)
+	if err := root.Execute(args("leaf", "-h")); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Flag constraints:",
+		"-name is required",
+		"one of -token, -token-file is required",
+		"-user requires -password",
+		"only one of -json, -yaml may be set",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("help missing %q:\n%s", want, buf)
+		}
+	}
+}
@@ -5,6 +5,10 @@ package cli
 
 import (
 	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
 )
 
 // Flags represents a type that sets options based on
@@ -31,9 +35,39 @@ func FlagFuncsAll(fs *flag.FlagSet, flagFuncs ...func(*flag.FlagSet)) {
 
 func createFlagSet(c *Command) *flag.FlagSet {
 	var fs *flag.FlagSet
-	if f := c.Flags(); f != nil {
+	if f := c.Flags(); f != nil || hasPersistentFlags(c) || c.configFlag != "" || c.hasOutput {
 		fs = flag.NewFlagSet(c.Name(), flag.ContinueOnError)
-		f.Flags(fs)
+		registerPersistentFlags(c, fs)
+		if f != nil {
+			f.Flags(fs)
+		}
+		if c.configFlag != "" {
+			registerConfigFlag(c, fs)
+		}
+		if c.hasOutput {
+			registerFormatFlag(c, fs)
+		}
+		// Keep the usages before they're annotated below, for prompts.
+		c.flagUsages = map[string]string{}
+		fs.VisitAll(func(f *flag.Flag) { c.flagUsages[f.Name] = f.Usage })
+		if c.gnuFlags {
+			// Sort the aliases so that help is the same every time.
+			shorts := map[string][]string{}
+			for alias, name := range flagAliases(c) {
+				shorts[name] = append(shorts[name], "-"+alias)
+			}
+			for name, aliases := range shorts {
+				if f := fs.Lookup(name); f != nil {
+					sort.Strings(aliases)
+					f.Usage = fmt.Sprintf("%s (short %s)", f.Usage, strings.Join(aliases, ", "))
+				}
+			}
+		}
+		if c.flagEnv {
+			fs.VisitAll(func(f *flag.Flag) {
+				f.Usage = fmt.Sprintf("%s (env $%s)", f.Usage, c.FlagEnvVar(f.Name))
+			})
+		}
 	}
 	if fh := c.flagHider; fh != nil {
 		for _, name := range fh.HideFlags() {
@@ -45,10 +79,99 @@ func createFlagSet(c *Command) *flag.FlagSet {
 
 func parseFlags(c *Command, args []string) ([]string, error) {
 	if c.flagSet = createFlagSet(c); c.flagSet == nil {
-		return args, nil
+		if !c.gnuFlags {
+			recordPersistentFlags(c)
+			return args, nil
+		}
+		// Still handle -- and -h in GNU mode.
+		c.flagSet = flag.NewFlagSet(c.Name(), flag.ContinueOnError)
+	}
+	// Errors are reported by ReportError, and help by printHelp.
+	c.flagSet.SetOutput(io.Discard)
+	if err := applyInheritedFlags(c); err != nil {
+		return nil, err
+	}
+	remaining, err := parseFlagSet(c, args)
+	if err != nil {
+		return nil, err
+	}
+	// Flags set on the command line take precedence over the environment, and
+	// both take precedence over the config file.
+	set := map[string]bool{}
+	c.flagSet.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if c.flagEnv {
+		if err := setFlagsFromEnv(c, set); err != nil {
+			return nil, err
+		}
+	}
+	if c.configFlag != "" {
+		if err := setFlagsFromConfig(c, set); err != nil {
+			return nil, err
+		}
+	}
+	if shouldPrompt(c) {
+		if err := promptForFlags(c); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkFlagConstraints(c); err != nil {
+		return nil, err
+	}
+	if c.hasOutput {
+		if err := checkOutputFormat(c.outputFormat); err != nil {
+			return nil, err
+		}
+	}
+	recordPersistentFlags(c)
+	return remaining, nil
+}
+
+func parseFlagSet(c *Command, args []string) ([]string, error) {
+	if c.gnuFlags {
+		interspersed := len(c.Subcommands()) == 0
+		return parseGNUFlags(c.flagSet, flagAliases(c), args, interspersed)
 	}
 	if err := c.flagSet.Parse(args); err != nil {
 		return nil, err
 	}
 	return c.flagSet.Args(), nil
 }
+
+// FlagEnvVar returns the name of the environment variable that sets the flag
+// called name when WithFlagEnv is used. It is made from the command path and
+// flag name, upper-cased, with any non-alphanumeric characters replaced by
+// underscores. E.g. the flag "dry-run" on "mycli sub" is MYCLI_SUB_DRY_RUN.
+func (c *Command) FlagEnvVar(name string) string {
+	s := strings.Join(append(c.Path(), name), "_")
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// setFlagsFromEnv sets any flags not already in set from their environment
+// variables, and adds them to set.
+func setFlagsFromEnv(c *Command, set map[string]bool) error {
+	var err error
+	c.flagSet.VisitAll(func(f *flag.Flag) {
+		if err != nil || set[f.Name] {
+			return
+		}
+		name := c.FlagEnvVar(f.Name)
+		value, ok := c.LookupEnv(name)
+		if !ok {
+			return
+		}
+		if setErr := c.flagSet.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value %q for $%s: %w", value, name, setErr)
+			return
+		}
+		set[f.Name] = true
+	})
+	return err
+}
@@ -0,0 +1,154 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// FlagAliaser can be implemented by options that implement Flags, to give
+// single-letter aliases to their flags when using WithGNUFlags. The returned
+// map is from alias to flag name, e.g. {"v": "verbose"}.
+type FlagAliaser interface {
+	FlagAliases() map[string]string
+}
+
+// WithGNUFlags makes the command parse its flags GNU-style rather than using
+// the standard library's flag package rules. In this mode:
+//
+//   - Flags may be given as --name or -name, with the value either after
+//     an equals sign or as the next arg.
+//   - Single-letter aliases (see FlagAliaser) may be given as -n, and aliases
+//     for boolean flags may be combined, e.g. -abc is the same as -a -b -c.
+//   - Leaf commands accept flags after positional args.
+//   - An arg of -- ends flag parsing, and all following args are positional.
+//
+// Flags are still defined using the Flags interface.
+func (c *Command) WithGNUFlags() *Command { c.gnuFlags = true; return c }
+
+func flagAliases(c *Command) map[string]string {
+	if c.flagAliaser == nil {
+		return nil
+	}
+	return c.flagAliaser.FlagAliases()
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// parseGNUFlags parses args into fs according to the rules described by
+// WithGNUFlags, and returns the positional args. If interspersed is false,
+// parsing stops at the first positional arg, which is needed to leave
+// subcommand args to be parsed by the subcommand.
+func parseGNUFlags(fs *flag.FlagSet, aliases map[string]string, args []string, interspersed bool) ([]string, error) {
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(positional, args[i+1:]...), nil
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			if !interspersed {
+				return append(positional, args[i:]...), nil
+			}
+			positional = append(positional, arg)
+			continue
+		}
+		// next consumes the following arg as a flag value.
+		next := func(name string) (string, error) {
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("flag needs an argument: -%s", name)
+			}
+			i++
+			return args[i], nil
+		}
+		var err error
+		if strings.HasPrefix(arg, "--") {
+			err = setLongFlag(fs, arg[2:], next)
+		} else if name, _, _ := strings.Cut(arg[1:], "="); len(name) > 1 && fs.Lookup(name) != nil {
+			err = setLongFlag(fs, arg[1:], next)
+		} else {
+			err = setShortFlags(fs, aliases, arg[1:], next)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return positional, nil
+}
+
+func setLongFlag(fs *flag.FlagSet, arg string, next func(string) (string, error)) error {
+	name, value, hasValue := strings.Cut(arg, "=")
+	f := fs.Lookup(name)
+	if f == nil {
+		return undefinedFlag(fs, name)
+	}
+	if !hasValue {
+		if isBoolFlag(f) {
+			value = "true"
+		} else {
+			var err error
+			if value, err = next(name); err != nil {
+				return err
+			}
+		}
+	}
+	return setFlag(fs, name, value)
+}
+
+func setShortFlags(fs *flag.FlagSet, aliases map[string]string, arg string, next func(string) (string, error)) error {
+	for j := 0; j < len(arg); j++ {
+		alias := arg[j : j+1]
+		name, ok := aliases[alias]
+		if !ok {
+			if fs.Lookup(alias) == nil {
+				return undefinedFlag(fs, alias)
+			}
+			name = alias
+		}
+		f := fs.Lookup(name)
+		if f == nil {
+			panic(fmt.Sprintf("flag alias -%s refers to undefined flag -%s", alias, name))
+		}
+		rest := arg[j+1:]
+		if isBoolFlag(f) {
+			if strings.HasPrefix(rest, "=") {
+				return setFlag(fs, name, rest[1:])
+			}
+			if err := setFlag(fs, name, "true"); err != nil {
+				return err
+			}
+			continue
+		}
+		// Non-bool flags consume the rest of the arg as their value,
+		// or else the next arg.
+		value := strings.TrimPrefix(rest, "=")
+		if rest == "" {
+			var err error
+			if value, err = next(alias); err != nil {
+				return err
+			}
+		}
+		return setFlag(fs, name, value)
+	}
+	return nil
+}
+
+func setFlag(fs *flag.FlagSet, name, value string) error {
+	if err := fs.Set(name, value); err != nil {
+		return fmt.Errorf("invalid value %q for flag -%s: %w", value, name, err)
+	}
+	return nil
+}
+
+func undefinedFlag(fs *flag.FlagSet, name string) error {
+	if name == "h" || name == "help" {
+		return flag.ErrHelp
+	}
+	return fmt.Errorf("flag provided but not defined: -%s", name)
+}
@@ -0,0 +1,99 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type gnuOpts struct {
+	all, verbose, force bool
+	name                string
+	count               int
+	args                []string
+}
+
+func (o *gnuOpts) Flags(fs *flag.FlagSet) {
+	fs.BoolVar(&o.all, "all", false, "all")
+	fs.BoolVar(&o.verbose, "verbose", false, "verbose")
+	fs.BoolVar(&o.force, "force", false, "force")
+	fs.StringVar(&o.name, "name", "", "name")
+	fs.IntVar(&o.count, "count", 0, "count")
+}
+
+func (o *gnuOpts) FlagAliases() map[string]string {
+	return map[string]string{"a": "all", "v": "verbose", "f": "force", "F": "force", "n": "name"}
+}
+
+func (o *gnuOpts) ParseArgs(args []string) error {
+	o.args = args
+	return nil
+}
+
+func TestWithGNUFlags(t *testing.T) {
+	cases := []struct {
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{args("leaf"), "false false false  0 []", ""},
+		{args("leaf", "--all", "--name=x", "--count", "3"), "true false false x 3 []", ""},
+		{args("leaf", "-all", "-name", "x"), "true false false x 0 []", ""},
+		{args("leaf", "-avf"), "true true true  0 []", ""},
+		{args("leaf", "-avn", "x"), "true true false x 0 []", ""},
+		{args("leaf", "-avnx"), "true true false x 0 []", ""},
+		{args("leaf", "-n=x"), "false false false x 0 []", ""},
+		{args("leaf", "a1", "-v", "a2", "--count=2", "a3"), "false true false  2 [a1 a2 a3]", ""},
+		{args("leaf", "-v", "--", "-a", "--name"), "false true false  0 [-a --name]", ""},
+		{args("leaf", "--all=false", "-v=false"), "false false false  0 []", ""},
+		{args("leaf", "-x"), "", "flag provided but not defined: -x"},
+		{args("leaf", "-avx"), "", "flag provided but not defined: -x"},
+		{args("leaf", "--nope"), "", "flag provided but not defined: -nope"},
+		{args("leaf", "--count"), "", "flag needs an argument: -count"},
+		{args("leaf", "--count=x"), "", `invalid value "x" for flag -count: parse error`},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(strings.Join(c.args[1:], " "), func(t *testing.T) {
+			var got string
+			root := RootCommand("root", "root command",
+				LeafCommand("leaf", "leaf command", func(o *gnuOpts) error {
+					got = fmt.Sprintf("%t %t %t %s %d %v", o.all, o.verbose, o.force, o.name, o.count, o.args)
+					return nil
+				}).WithGNUFlags(),
+			)
+			err := root.Execute(c.args)
+			if c.wantErr != "" {
+				if err == nil || err.Error() != c.wantErr {
+					t.Fatalf("got error %v; want %q", err, c.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("got %q; want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestWithGNUFlags_aliasUsage(t *testing.T) {
+	leaf := LeafCommand("leaf", "leaf command", func(*gnuOpts) error { return nil }).WithGNUFlags()
+	RootCommand("root", "root command", leaf)
+	// Aliases come from a map, so check repeatedly to catch random ordering.
+	for i := 0; i < 20; i++ {
+		fs := createFlagSet(leaf)
+		if got, want := fs.Lookup("force").Usage, "force (short -F, -f)"; got != want {
+			t.Fatalf("got usage %q; want %q", got, want)
+		}
+		if got, want := fs.Lookup("verbose").Usage, "verbose (short -v)"; got != want {
+			t.Fatalf("got usage %q; want %q", got, want)
+		}
+	}
+}
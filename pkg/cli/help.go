@@ -0,0 +1,381 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Example is an example invocation of a command, shown in its help.
+type Example struct {
+	// Description says what the example does.
+	Description string
+	// Args are the args following the command's path, e.g. "-v ./dir".
+	Args string
+}
+
+// WithExample adds an example to c's help.
+func (c *Command) WithExample(description, args string) *Command {
+	c.examples = append(c.examples, Example{description, args})
+	return c
+}
+
+// WithCategory sets the category c is listed under in its parent's help.
+// Commands without a category are listed first.
+func (c *Command) WithCategory(category string) *Command { c.category = category; return c }
+
+func (c *Command) Examples() []Example { return c.examples }
+func (c *Command) Category() string    { return c.category }
+
+// EnvDocumenter can be implemented by options that implement Env, to document
+// the environment variables they read in the command's help. The returned map
+// is from variable name to description.
+type EnvDocumenter interface {
+	EnvDocs() map[string]string
+}
+
+// ArgDocumenter can be implemented by options that implement ArgDefiner, to
+// describe their args in the command's help. The returned map is from arg name
+// to description.
+type ArgDocumenter interface {
+	ArgDocs() map[string]string
+}
+
+type helpOpts struct {
+	path []string
+}
+
+func (o *helpOpts) Args(al *ArgList) {
+	OptionalVariadicArg(al, &o.path, "command", StringParser)
+}
+
+// HelpCommand returns a command that prints the help for the command at a
+// given path in whichever command tree it's mounted in, e.g. "mycli help sub
+// leaf" prints the same help as "mycli sub leaf -h". It's intended to be passed
+// to RootCommand, where it becomes the "help" subcommand.
+func HelpCommand() *Command {
+	var c *Command
+	c = LeafCommand("help", "show help for a command", func(o *helpOpts) error {
+		target := c.root()
+		for _, name := range o.path {
+			sub, err := findSubcommand(target, name)
+			if err != nil {
+				return usageError(target, err)
+			}
+			target = sub
+		}
+		return target.printHelp(c.stdout)
+	})
+	return c
+}
+
+const (
+	defaultHelpWidth = 80
+	minHelpWidth     = 40
+)
+
+// helpWidth returns the width to wrap help written to w at. This is taken from
+// $COLUMNS if it's set, or else the terminal's width if w is a terminal, or
+// else defaultHelpWidth.
+func helpWidth(c *Command, w io.Writer) int {
+	width := defaultHelpWidth
+	if v, ok := c.LookupEnv("COLUMNS"); ok {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			width = n
+		}
+	} else if f, ok := w.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		if n, _, err := term.GetSize(int(f.Fd())); err == nil && n > 0 {
+			width = n
+		}
+	}
+	return max(width, minHelpWidth)
+}
+
+func (c *Command) printHelp(w io.Writer) error {
+	width := helpWidth(c, w)
+	sections := []string{fmt.Sprintf("%s - %s", c.name, c.desc)}
+	if notice := stateNotice(c); notice != "" {
+		sections = append(sections, wrap(notice, width, 0))
+	}
+	sections = append(sections, "Usage:\n\n"+usageHelp(c, width))
+	if help := strings.TrimSpace(c.help); help != "" {
+		sections = append(sections, wrap(help, width, 0))
+	}
+	for _, s := range []string{
+		flagsHelp(c, width),
+		argsHelp(c, width),
+		envHelp(c, width),
+		constraintsHelp(c, width),
+		examplesHelp(c, width),
+		subcommandsHelp(c, width),
+	} {
+		if s != "" {
+			sections = append(sections, s)
+		}
+	}
+	_, err := io.WriteString(w, strings.Join(sections, "\n\n")+"\n")
+	return err
+}
+
+// usageHelp returns c's usage line, wrapped with a hanging indent.
+func usageHelp(c *Command, width int) string {
+	lines := wrapWords(strings.Fields(usageLine(c)), max(width-4, minWrapWidth))
+	return "  " + strings.Join(lines, "\n    ")
+}
+
+func usageLine(c *Command) string {
+	line := strings.TrimSpace(c.PathString() + " " + c.Synopsis())
+	if len(visibleSubcommands(c)) != 0 {
+		line += " SUBCOMMAND"
+	}
+	return line
+}
+
+func flagsHelp(c *Command, width int) string {
+	fs := createFlagSet(c)
+	if fs == nil {
+		return ""
+	}
+	b := &strings.Builder{}
+	b.WriteString("Flags:\n")
+	fs.VisitAll(func(f *flag.Flag) {
+		typeName, usage := flag.UnquoteUsage(f)
+		fmt.Fprintf(b, "\n  -%s", f.Name)
+		if typeName != "" {
+			fmt.Fprintf(b, " %s", typeName)
+		}
+		if !isZeroDefault(f) {
+			if typeName == "string" {
+				usage += fmt.Sprintf(" (default %q)", f.DefValue)
+			} else {
+				usage += fmt.Sprintf(" (default %s)", f.DefValue)
+			}
+		}
+		if usage != "" {
+			fmt.Fprintf(b, "\n%s", wrap(usage, width, 6))
+		}
+	})
+	return b.String()
+}
+
+// isZeroDefault reports whether f's default is the zero value of its type, in
+// which case it isn't worth showing. This works the same way as the flag
+// package's PrintDefaults.
+func isZeroDefault(f *flag.Flag) (zero bool) {
+	t := reflect.TypeOf(f.Value)
+	var z reflect.Value
+	if t.Kind() == reflect.Pointer {
+		z = reflect.New(t.Elem())
+	} else {
+		z = reflect.Zero(t)
+	}
+	v, ok := z.Interface().(flag.Value)
+	if !ok {
+		return false
+	}
+	// String methods may not handle the zero value of their type.
+	defer func() {
+		if recover() != nil {
+			zero = false
+		}
+	}()
+	return f.DefValue == v.String()
+}
+
+func argsHelp(c *Command, width int) string {
+	argList := makeArgList(c)
+	if len(argList) == 0 {
+		return ""
+	}
+	docs := map[string]string{}
+	if d, ok := c.opts.(ArgDocumenter); ok {
+		for name, doc := range d.ArgDocs() {
+			docs[strings.ToUpper(name)] = doc
+		}
+	}
+	var defs []definition
+	for _, a := range argList {
+		name, def := a.displayName(""), a.defaultVal
+		if a.variadic {
+			name, def = a.displayName("..."), strings.Join(a.defaultVals, " ")
+		}
+		desc := docs[a.name]
+		if a.fromInput {
+			desc = strings.TrimSpace(desc + " (use - to read values from stdin, or @FILE to read them from FILE)")
+		}
+		switch {
+		case a.required:
+			desc = strings.TrimSpace(desc + " (required)")
+		case def != "":
+			desc = strings.TrimSpace(fmt.Sprintf("%s (default %s)", desc, def))
+		}
+		defs = append(defs, definition{name, desc})
+	}
+	return "Arguments:\n\n" + definitions(defs, width)
+}
+
+func envHelp(c *Command, width int) string {
+	docs := map[string]string{}
+	if d, ok := c.opts.(EnvDocumenter); ok {
+		for name, doc := range d.EnvDocs() {
+			docs[name] = doc
+		}
+	}
+	if c.configEnv != "" {
+		docs[c.configEnv] = fmt.Sprintf("path to a config file, as for -%s", c.configFlag)
+	}
+	if len(docs) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(docs))
+	for name := range docs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	defs := make([]definition, len(names))
+	for i, name := range names {
+		defs[i] = definition{name, docs[name]}
+	}
+	return "Environment variables:\n\n" + definitions(defs, width)
+}
+
+func constraintsHelp(c *Command, width int) string {
+	constraints := flagConstraints(c)
+	if len(constraints) == 0 {
+		return ""
+	}
+	lines := make([]string, len(constraints))
+	for i, fc := range constraints {
+		lines[i] = wrap(fc.String(), width, 2)
+	}
+	return "Flag constraints:\n\n" + strings.Join(lines, "\n")
+}
+
+func examplesHelp(c *Command, width int) string {
+	if len(c.examples) == 0 {
+		return ""
+	}
+	examples := make([]string, len(c.examples))
+	for i, e := range c.examples {
+		examples[i] = fmt.Sprintf("%s\n    $ %s %s", wrap(e.Description, width, 2), c.PathString(), e.Args)
+	}
+	return "Examples:\n\n" + strings.Join(examples, "\n\n")
+}
+
+func subcommandsHelp(c *Command, width int) string {
+	subs := visibleSubcommands(c)
+	if len(subs) == 0 {
+		return ""
+	}
+	// Use the same column width for all categories so they line up.
+	col := 0
+	for _, s := range subs {
+		col = max(col, len(s.Name()))
+	}
+	var categories []string
+	byCategory := map[string][]definition{}
+	for _, s := range subs {
+		if _, ok := byCategory[s.category]; !ok && s.category != "" {
+			categories = append(categories, s.category)
+		}
+		byCategory[s.category] = append(byCategory[s.category], definition{s.Name(), stateLabel(s) + s.Description()})
+	}
+	var sections []string
+	if defs := byCategory[""]; len(defs) != 0 {
+		sections = append(sections, "Subcommands:\n\n"+definitionsAt(defs, col, width))
+	}
+	for _, cat := range categories {
+		sections = append(sections, cat+":\n\n"+definitionsAt(byCategory[cat], col, width))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// definition is a term and its description, for lists of subcommands, args etc.
+type definition struct {
+	term, desc string
+}
+
+func definitions(defs []definition, width int) string {
+	col := 0
+	for _, d := range defs {
+		col = max(col, len(d.term))
+	}
+	return definitionsAt(defs, col, width)
+}
+
+// definitionsAt writes defs indented by two spaces, with their descriptions
+// wrapped in a column starting two spaces after col.
+func definitionsAt(defs []definition, col, width int) string {
+	indent := 2 + col + 2
+	lines := make([]string, len(defs))
+	for i, d := range defs {
+		if d.desc == "" {
+			lines[i] = "  " + d.term
+			continue
+		}
+		desc := strings.TrimLeft(wrap(d.desc, width, indent), " ")
+		lines[i] = fmt.Sprintf("  %-*s  %s", col, d.term, desc)
+	}
+	return strings.Join(lines, "\n")
+}
+
+const minWrapWidth = 20
+
+// wrap word-wraps s to width columns, indenting every line by indent spaces.
+// Blank lines separate paragraphs, and lines starting with whitespace are kept
+// as they are, so that preformatted text such as code isn't wrapped.
+func wrap(s string, width, indent int) string {
+	width = max(width-indent, minWrapWidth)
+	pad := strings.Repeat(" ", indent)
+	var out, para []string
+	flush := func() {
+		for _, l := range wrapWords(strings.Fields(strings.Join(para, " ")), width) {
+			out = append(out, pad+l)
+		}
+		para = nil
+	}
+	for _, line := range strings.Split(s, "\n") {
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+			out = append(out, "")
+		case line[0] == ' ' || line[0] == '\t':
+			flush()
+			out = append(out, pad+line)
+		default:
+			para = append(para, line)
+		}
+	}
+	flush()
+	return strings.Join(out, "\n")
+}
+
+func wrapWords(words []string, width int) []string {
+	var lines []string
+	var line string
+	for _, word := range words {
+		switch {
+		case line == "":
+			line = word
+		case len(line)+1+len(word) > width:
+			lines = append(lines, line)
+			line = word
+		default:
+			line += " " + word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,152 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/composite-action-framework-go/pkg/testhelpers/assert"
+	"github.com/hashicorp/composite-action-framework-go/pkg/testhelpers/goldenfile"
+)
+
+type deployOpts struct {
+	env      string
+	timeout  time.Duration
+	dryRun   bool
+	target   string
+	services []string
+}
+
+func (o *deployOpts) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&o.env, "env", "staging", "the environment to deploy to, which must already exist and have been provisioned by the infrastructure pipeline")
+	fs.DurationVar(&o.timeout, "timeout", time.Minute, "how long to wait for the deployment to become healthy")
+	fs.BoolVar(&o.dryRun, "dry-run", false, "only print what would be done")
+}
+
+func (o *deployOpts) Args(al *ArgList) {
+	al.Required(&o.target, "target")
+	al.OptionalVariadic(&o.services, "service", "all")
+}
+
+func (o *deployOpts) ArgDocs() map[string]string {
+	return map[string]string{
+		"target":  "the name of the deployment target",
+		"service": "services to deploy",
+	}
+}
+
+func (o *deployOpts) ReadEnv() error { return nil }
+
+func (o *deployOpts) EnvDocs() map[string]string {
+	return map[string]string{"DEPLOY_TOKEN": "token used to authenticate with the deployment API"}
+}
+
+func helpTestRoot() *Command {
+	leaf := func(name, desc string) *Command {
+		return LeafCommand(name, desc, func(None) error { return nil })
+	}
+	return RootCommand("mycli", "manage things",
+		LeafCommand("deploy", "deploy a service", func(*deployOpts) error { return nil }).
+			WithHelp(`
+Deploy deploys one or more services to a target. Services are deployed in the
+order given, and each must become healthy before the next is deployed.
+
+To deploy everything to production:
+
+    mycli deploy -env=production prod-1
+`).
+			WithExample("Deploy the api service to target t1 in staging.", "t1 api").
+			WithExample("See what deploying everything would do.", "-dry-run t1").
+			WithCategory("Release commands").
+			WithConfigFile("config", "MYCLI_CONFIG"),
+		leaf("rollback", "roll back the most recent deployment of a service").WithCategory("Release commands"),
+		leaf("status", "show the status of all services"),
+		leaf("version", "print the version"),
+		leaf("lint", "check configuration for errors and questionable settings that are likely to cause deployment failures").
+			WithCategory("Development commands").WithExperimental(),
+		HelpCommand(),
+	)
+}
+
+func TestPrintHelp(t *testing.T) {
+	cases := []struct {
+		desc  string
+		args  []string
+		width string
+	}{
+		{"root", args("-h"), "80"},
+		{"root_narrow", args("-h"), "50"},
+		{"leaf", args("deploy", "-h"), "80"},
+		{"leaf_narrow", args("deploy", "-h"), "50"},
+		{"help_command", args("help", "deploy"), "80"},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			goldenfile.Do(t, func(got *os.File) {
+				root := helpTestRoot()
+				root.SetStdout(got)
+				root.SetLookupEnv(func(name string) (string, bool) {
+					return c.width, name == "COLUMNS"
+				})
+				if err := root.Execute(c.args); err != nil {
+					t.Fatal(err)
+				}
+			})
+		})
+	}
+}
+
+func TestCommand_HelpAndUsage(t *testing.T) {
+	root := helpTestRoot()
+	root.SetLookupEnv(func(name string) (string, bool) { return "80", name == "COLUMNS" })
+	deploy, err := findSubcommand(root, "deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	root.SetStdout(buf)
+	if err := root.Execute(args("deploy", "-h")); err != nil {
+		t.Fatal(err)
+	}
+	help := strings.TrimSpace(buf.String())
+	assert.Equal(t, deploy.Help(), help)
+
+	usage := deploy.Usage()
+	if !strings.HasPrefix(usage, "Usage:\n\n  mycli deploy ") || !strings.Contains(usage, "\n\nFlags:\n") || !strings.Contains(usage, "\n  -dry-run\n") {
+		t.Errorf("unexpected usage:\n%s", usage)
+	}
+	// Usage is made of the same sections as help, but without the text between
+	// them.
+	usageSection, flagsSection, _ := strings.Cut(usage, "\n\nFlags:")
+	if !strings.Contains(help, usageSection) || !strings.Contains(help, "Flags:"+flagsSection) {
+		t.Errorf("help doesn't contain usage; help:\n%s\nusage:\n%s", help, usage)
+	}
+}
+
+func TestHelpCommand_notFound(t *testing.T) {
+	root := helpTestRoot()
+	root.SetStdout(&bytes.Buffer{})
+	err := root.Execute(args("help", "deplyo"))
+	if err == nil || !strings.HasPrefix(err.Error(), `subcommand "deplyo" not found; did you mean "deploy"?`) {
+		t.Errorf("got error %v", err)
+	}
+	assert.Equal(t, ExitCode(err), ExitCodeUsage)
+}
+
+func TestWrap(t *testing.T) {
+	in := "one two three four five six seven eight nine ten eleven twelve\n\n    code block that is not wrapped at all\nthirteen"
+	want := `  one two three four five six
+  seven eight nine ten eleven
+  twelve
+
+      code block that is not wrapped at all
+  thirteen`
+	assert.Equal(t, wrap(in, 30, 2), want)
+}
@@ -0,0 +1,47 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// Main runs root with os.Args and exits the process. It's intended to be the
+// only call in a CLI's main function:
+//
+//	func main() { cli.Main(root) }
+//
+// If running the command fails, the error is printed to root's stderr, along
+// with usage information for usage errors, and the process exits with the code
+// returned by ExitCode.
+func Main(root *Command) {
+	exit(MainCode(root, os.Args))
+}
+
+// MainCode is like Main except that it uses args and returns the exit code
+// instead of exiting.
+func MainCode(root *Command, args []string) int {
+	return ReportError(root, root.ExecuteContext(context.Background(), args))
+}
+
+// ReportError prints err, returned from executing root, to root's stderr in the
+// same way as Main, and returns its exit code. Nil errors and flag.ErrHelp
+// aren't printed, and return ExitCodeOK.
+func ReportError(root *Command, err error) int {
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return ExitCodeOK
+	}
+	fmt.Fprintf(root.stderr, "Error: %s\n", err)
+	var ue *UsageError
+	if errors.As(err, &ue) && ue.Command != nil {
+		c := ue.Command
+		fmt.Fprintf(root.stderr, "Usage: %s\n", usageLine(c))
+		fmt.Fprintf(root.stderr, "Run '%s -h' for help.\n", c.PathString())
+	}
+	return ExitCode(err)
+}
@@ -0,0 +1,112 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// RunFunc runs a command.
+type RunFunc func(ctx context.Context) error
+
+// Middleware wraps the running of command c, by returning a RunFunc that
+// calls next, or deliberately doesn't. It is called after c's flags and args
+// are parsed and its options initialised, so c.Options returns its resolved
+// options.
+type Middleware func(c *Command, next RunFunc) RunFunc
+
+// PreRunHook is called before a command is run. If it returns an error the
+// command isn't run, and the error is returned as if the command failed.
+type PreRunHook func(ctx context.Context, c *Command) error
+
+// PostRunHook is called after a command is run, with the error it returned
+// (or the error returned by a PreRunHook). The error returned by the hook
+// replaces the command's error, so hooks that don't handle errors should
+// return err unchanged.
+type PostRunHook func(ctx context.Context, c *Command, err error) error
+
+// WithMiddleware adds middleware to c, which applies to c and all of its
+// subcommands. When a command runs, middleware from the root command is
+// outermost, and within each command middleware is applied in the order it was
+// added, so the first is outermost.
+func (c *Command) WithMiddleware(m ...Middleware) *Command {
+	c.middleware = append(c.middleware, m...)
+	return c
+}
+
+// WithPreRun adds hooks that run before c or any of its subcommands is run.
+// Pre-run hooks run from the root command down, after all middleware.
+func (c *Command) WithPreRun(h ...PreRunHook) *Command {
+	c.preRun = append(c.preRun, h...)
+	return c
+}
+
+// WithPostRun adds hooks that run after c or any of its subcommands is run.
+// Post-run hooks run in the reverse order to pre-run hooks, i.e. from the
+// command being run up to the root, and always run even if a pre-run hook or
+// the command fails.
+func (c *Command) WithPostRun(h ...PostRunHook) *Command {
+	c.postRun = append(c.postRun, h...)
+	return c
+}
+
+// Options returns the options struct passed to the command's run function, or
+// nil if it isn't a leaf command.
+func (c *Command) Options() any { return c.opts }
+
+// Recover is middleware that turns panics in the commands it wraps into
+// errors, including the stack trace.
+func Recover(c *Command, next RunFunc) RunFunc {
+	return func(ctx context.Context) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%s: panic: %v\n%s", c.PathString(), r, debug.Stack())
+			}
+		}()
+		return next(ctx)
+	}
+}
+
+// runWithHooks runs c wrapped in the middleware and hooks of it and its
+// ancestors. Root commands, which just print help, are run without them.
+func runWithHooks(ctx context.Context, c *Command) error {
+	if c.opts == nil {
+		return c.run(ctx)
+	}
+	cmds := c.ancestry()
+	var pre []PreRunHook
+	var post []PostRunHook
+	for _, a := range cmds {
+		pre = append(pre, a.preRun...)
+		post = append(post, a.postRun...)
+	}
+	var f RunFunc = func(ctx context.Context) error {
+		err := runPreHooks(ctx, c, pre)
+		if err == nil {
+			err = c.run(ctx)
+		}
+		for i := len(post) - 1; i >= 0; i-- {
+			err = post[i](ctx, c, err)
+		}
+		return err
+	}
+	for i := len(cmds) - 1; i >= 0; i-- {
+		mw := cmds[i].middleware
+		for j := len(mw) - 1; j >= 0; j-- {
+			f = mw[j](c, f)
+		}
+	}
+	return f(ctx)
+}
+
+func runPreHooks(ctx context.Context, c *Command, hooks []PreRunHook) error {
+	for _, h := range hooks {
+		if err := h(ctx, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
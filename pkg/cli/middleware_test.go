@@ -0,0 +1,101 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type hookOpts struct {
+	name string
+}
+
+func (o *hookOpts) ParseArgs(args []string) error {
+	o.name = strings.Join(args, " ")
+	return nil
+}
+
+func TestMiddlewareAndHooks(t *testing.T) {
+	errRun := errors.New("run failed")
+	errPre := errors.New("pre failed")
+	cases := []struct {
+		desc    string
+		args    []string
+		runErr  error
+		preErr  error
+		want    string
+		wantErr error
+	}{
+		{"success", args("sub", "leaf", "x"), nil, nil,
+			"mw root 1 > mw root 2 > mw sub > pre root(x) > pre sub > run > post sub(<nil>) > post root(<nil>) < mw sub < mw root 2 < mw root 1", nil},
+		{"run error", args("sub", "leaf"), errRun, nil,
+			"mw root 1 > mw root 2 > mw sub > pre root() > pre sub > run > post sub(run failed) > post root(run failed) < mw sub < mw root 2 < mw root 1", errRun},
+		{"pre error", args("sub", "leaf"), nil, errPre,
+			"mw root 1 > mw root 2 > mw sub > pre root() > post sub(pre failed) > post root(pre failed) < mw sub < mw root 2 < mw root 1", errPre},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			var events []string
+			event := func(format string, a ...any) { events = append(events, fmt.Sprintf(format, a...)) }
+			mw := func(name string) Middleware {
+				return func(cmd *Command, next RunFunc) RunFunc {
+					return func(ctx context.Context) error {
+						event("mw %s", name)
+						err := next(ctx)
+						event("< mw %s", name)
+						return err
+					}
+				}
+			}
+			root := RootCommand("root", "root command",
+				RootCommand("sub", "sub command",
+					LeafCommand("leaf", "leaf command", func(*hookOpts) error {
+						event("run")
+						return c.runErr
+					}),
+				).WithMiddleware(mw("sub")).
+					WithPreRun(func(context.Context, *Command) error { event("pre sub"); return nil }).
+					WithPostRun(func(_ context.Context, _ *Command, err error) error { event("post sub(%v)", err); return err }),
+			).WithMiddleware(mw("root 1"), mw("root 2")).
+				WithPreRun(func(_ context.Context, cmd *Command) error {
+					event("pre root(%s)", cmd.Options().(*hookOpts).name)
+					if cmd.PathString() != "root sub leaf" {
+						t.Errorf("got command %q", cmd.PathString())
+					}
+					return c.preErr
+				}).
+				WithPostRun(func(_ context.Context, _ *Command, err error) error { event("post root(%v)", err); return err })
+
+			err := root.Execute(c.args)
+			if !errors.Is(err, c.wantErr) {
+				t.Errorf("got error %v; want %v", err, c.wantErr)
+			}
+			got := strings.Join(events, " > ")
+			got = strings.ReplaceAll(got, " > <", " <")
+			if got != c.want {
+				t.Errorf("got events:\n%s\nwant:\n%s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestRecover(t *testing.T) {
+	root := RootCommand("root", "root command",
+		LeafCommand("leaf", "leaf command", func(*None) error {
+			panic("oops")
+		}),
+	).WithMiddleware(Recover)
+	err := root.Execute(args("leaf"))
+	if err == nil || !strings.HasPrefix(err.Error(), "root leaf: panic: oops\n") {
+		t.Fatalf("got error %v", err)
+	}
+	if ExitCode(err) != ExitCodeRuntime {
+		t.Errorf("got exit code %d", ExitCode(err))
+	}
+}
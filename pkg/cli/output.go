@@ -0,0 +1,179 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"context"
+	"encoding"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/composite-action-framework-go/pkg/json"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by the -format flag of commands created using
+// OutputCommand.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTSV  = "tsv"
+)
+
+// OutputFormats lists the valid values of the -format flag.
+var OutputFormats = []string{FormatText, FormatJSON, FormatYAML, FormatTSV}
+
+// FormatFlag is the name of the flag added by OutputCommand.
+const FormatFlag = "format"
+
+// Table can be implemented by command results to render them as a table in
+// the text and tsv output formats. In the json and yaml formats the result
+// itself is encoded as usual.
+type Table interface {
+	Header() []string
+	Rows() [][]string
+}
+
+// TableOf returns a Table with one row per datum, made by calling row. When
+// encoded as JSON or YAML it's encoded as data.
+func TableOf[T any](data []T, header []string, row func(datum T) []string) Table {
+	return tableOf[T]{data, header, row}
+}
+
+type tableOf[T any] struct {
+	data   []T
+	header []string
+	row    func(T) []string
+}
+
+func (t tableOf[T]) Header() []string { return t.header }
+
+func (t tableOf[T]) Rows() [][]string {
+	rows := make([][]string, len(t.data))
+	for i, d := range t.data {
+		rows[i] = t.row(d)
+	}
+	return rows
+}
+
+func (t tableOf[T]) MarshalJSON() ([]byte, error) {
+	s, err := json.String(t.data)
+	return []byte(s), err
+}
+
+func (t tableOf[T]) MarshalYAML() (any, error) { return t.data, nil }
+
+// OutputCommand is like LeafCommandContext except that the run function returns
+// a result, which is written to the command's stdout in the format chosen
+// using its -format flag (see OutputFormats). The opts type must not define
+// its own -format flag.
+//
+// Formats are rendered as follows:
+//
+//   - text: Tables are written using TabWrite, with their header first.
+//     Tabs in cells are replaced by spaces.
+//     Other results are written using their String or MarshalText method
+//     if they have one, or else using fmt.Println.
+//   - tsv: Tables are written as tab-separated values, with their header
+//     first. Tabs, newlines and backslashes in cells are escaped as \t, \n
+//     and \\. Other results are written as for text.
+//   - json: The result is written using json.Write.
+//   - yaml: The result is written as YAML.
+func OutputCommand[T, R any](name, desc string, run func(ctx context.Context, opts *T) (R, error)) *Command {
+	var c *Command
+	c = LeafCommandContext(name, desc, func(ctx context.Context, opts *T) error {
+		result, err := run(ctx, opts)
+		if err != nil {
+			return err
+		}
+		return WriteOutput(c.stdout, c.outputFormat, result)
+	})
+	c.outputFormat = FormatText
+	c.hasOutput = true
+	return c
+}
+
+func registerFormatFlag(c *Command, fs *flag.FlagSet) {
+	usage := fmt.Sprintf("output format; one of %s", strings.Join(OutputFormats, ", "))
+	fs.StringVar(&c.outputFormat, FormatFlag, FormatText, usage)
+}
+
+func checkOutputFormat(format string) error {
+	for _, f := range OutputFormats {
+		if format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid value %q for flag -%s: must be one of %s",
+		format, FormatFlag, strings.Join(OutputFormats, ", "))
+}
+
+// WriteOutput writes v to w in format, as described by OutputCommand.
+func WriteOutput(w io.Writer, format string, v any) error {
+	if err := checkOutputFormat(format); err != nil {
+		return err
+	}
+	switch format {
+	case FormatJSON:
+		return json.Write(w, v)
+	case FormatYAML:
+		e := yaml.NewEncoder(w)
+		e.SetIndent(2)
+		if err := e.Encode(v); err != nil {
+			return err
+		}
+		return e.Close()
+	}
+	t, ok := v.(Table)
+	if !ok {
+		return writeText(w, v)
+	}
+	rows := t.Rows()
+	if h := t.Header(); len(h) != 0 {
+		rows = append([][]string{h}, rows...)
+	}
+	if format == FormatTSV {
+		for _, r := range rows {
+			if _, err := fmt.Fprintln(w, strings.Join(tsvEscape(r), "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return TabWrite(w, rows, func(r []string) string {
+		cells := make([]string, len(r))
+		for i, c := range r {
+			cells[i] = strings.ReplaceAll(c, "\t", " ")
+		}
+		return strings.Join(cells, "\t")
+	})
+}
+
+func writeText(w io.Writer, v any) error {
+	switch t := v.(type) {
+	case fmt.Stringer:
+		v = t.String()
+	case encoding.TextMarshaler:
+		b, err := t.MarshalText()
+		if err != nil {
+			return err
+		}
+		v = string(b)
+	}
+	_, err := fmt.Fprintln(w, v)
+	return err
+}
+
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+func tsvEscape(cells []string) []string {
+	out := make([]string, len(cells))
+	for i, c := range cells {
+		out[i] = tsvEscaper.Replace(c)
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type thing struct {
+	Name  string `json:"name" yaml:"name"`
+	Count int    `json:"count" yaml:"count"`
+}
+
+// things is a Table, which is encoded as a list of things in JSON and YAML.
+type things []thing
+
+func (ts things) Header() []string { return []string{"NAME", "COUNT"} }
+
+func (ts things) Rows() [][]string {
+	rows := make([][]string, len(ts))
+	for i, t := range ts {
+		rows[i] = []string{t.Name, fmt.Sprint(t.Count)}
+	}
+	return rows
+}
+
+func thingsTable(ts things) Table {
+	return TableOf(ts, []string{"NAME", "COUNT"}, func(t thing) []string {
+		return []string{t.Name, fmt.Sprint(t.Count)}
+	})
+}
+
+type outputOpts struct{}
+
+func TestOutputCommand(t *testing.T) {
+	ts := things{{"a", 1}, {"long name", 22}, {"tab\there", 3}}
+	cases := []struct {
+		args    []string
+		result  any
+		want    string
+		wantErr string
+	}{
+		{args("leaf"), ts, "NAME       COUNT\na          1\nlong name  22\ntab here   3\n", ""},
+		{args("leaf", "-format=tsv"), ts, "NAME\tCOUNT\na\t1\nlong name\t22\ntab\\there\t3\n", ""},
+		{args("leaf", "-format=json"), ts[:2], `[
+  {
+    "name": "a",
+    "count": 1
+  },
+  {
+    "name": "long name",
+    "count": 22
+  }
+]
+`, ""},
+		{args("leaf", "-format=yaml"), ts[:2], "- name: a\n  count: 1\n- name: long name\n  count: 22\n", ""},
+		{args("leaf", "-format=yaml"), ts[0], "name: a\ncount: 1\n", ""},
+		{args("leaf", "-format=tsv"), thingsTable(ts[:1]), "NAME\tCOUNT\na\t1\n", ""},
+		{args("leaf", "-format=yaml"), thingsTable(ts[:1]), "- name: a\n  count: 1\n", ""},
+		{args("leaf"), "hello", "hello\n", ""},
+		{args("leaf", "-format=tsv"), 42, "42\n", ""},
+		{args("leaf", "-format=xml"), "hello", "", "invalid value \"xml\" for flag -format: must be one of text, json, yaml, tsv"},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(strings.Join(c.args[1:], " "), func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			root := RootCommand("root", "root command",
+				OutputCommand("leaf", "leaf command", func(context.Context, *outputOpts) (any, error) {
+					return c.result, nil
+				}),
+			)
+			root.SetStdout(stdout)
+			root.SetStderr(&bytes.Buffer{})
+			err := root.Execute(c.args)
+			if c.wantErr != "" {
+				if err == nil || err.Error() != c.wantErr {
+					t.Fatalf("got error %v; want %q", err, c.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := stdout.String(); got != c.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestOutputCommand_typedResult(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	root := RootCommand("root", "root command",
+		OutputCommand("leaf", "leaf command", func(context.Context, *outputOpts) (things, error) {
+			return things{{"a", 1}}, nil
+		}),
+	)
+	root.SetStdout(stdout)
+	if err := root.Execute(args("leaf", "-format=tsv")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := stdout.String(), "NAME\tCOUNT\na\t1\n"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/composite-action-framework-go/pkg/internal/workflowcommand"
+)
+
+// Parallel configures RunParallel.
+type Parallel struct {
+	// Limit is the maximum number of items run at once. If it's less than 1,
+	// runtime.GOMAXPROCS(0) is used.
+	Limit int
+	// Groups writes each item's output between ::group:: and ::endgroup::
+	// workflow commands, so it's collapsed in the GitHub Actions log, and
+	// writes an ::error:: workflow command for each failed item. Otherwise
+	// each item's output is preceded by a header line.
+	Groups bool
+}
+
+// ItemError is the error returned by running a single item in RunParallel.
+type ItemError struct {
+	Name string
+	Err  error
+}
+
+func (e *ItemError) Error() string { return fmt.Sprintf("%s: %s", e.Name, e.Err) }
+func (e *ItemError) Unwrap() error { return e.Err }
+
+// ParallelError is returned by RunParallel when any items fail. It lists the
+// failures in the same order as the items.
+type ParallelError struct {
+	Total    int
+	Failures []*ItemError
+}
+
+func (e *ParallelError) Error() string {
+	lines := []string{fmt.Sprintf("%d of %d failed:", len(e.Failures), e.Total)}
+	for _, f := range e.Failures {
+		lines = append(lines, "  "+strings.ReplaceAll(f.Error(), "\n", "\n  "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *ParallelError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, f := range e.Failures {
+		errs[i] = f
+	}
+	return errs
+}
+
+// ExitCode returns the highest exit code of the failed items.
+func (e *ParallelError) ExitCode() int {
+	code := ExitCodeOK
+	for _, f := range e.Failures {
+		code = max(code, ExitCode(f.Err))
+	}
+	return code
+}
+
+// RunParallel calls run for each of items, running up to p.Limit at once.
+// Each item's output is buffered and written to w once it's finished and all
+// earlier items have been written, so output is never interleaved and always
+// appears in the same order as items. Items are named by calling name, for use
+// in output and errors.
+//
+// If ctx is cancelled, items that haven't started yet fail with ctx.Err().
+// If any items fail, a *ParallelError is returned.
+func RunParallel[I any](ctx context.Context, w io.Writer, p Parallel, items []I, name func(I) string, run func(ctx context.Context, item I, out io.Writer) error) error {
+	limit := p.Limit
+	if limit < 1 {
+		limit = runtime.GOMAXPROCS(0)
+	}
+	type result struct {
+		out  bytes.Buffer
+		err  error
+		done chan struct{}
+	}
+	results := make([]*result, len(items))
+	for i := range results {
+		results[i] = &result{done: make(chan struct{})}
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	go func() {
+		for i, item := range items {
+			r := results[i]
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				r.err = ctx.Err()
+				close(r.done)
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer func() { <-sem; close(r.done); wg.Done() }()
+				if err := ctx.Err(); err != nil {
+					r.err = err
+					return
+				}
+				r.err = run(ctx, item, &r.out)
+			}()
+		}
+	}()
+
+	pe := &ParallelError{Total: len(items)}
+	var writeErr error
+	for i, item := range items {
+		r := results[i]
+		<-r.done
+		n := name(item)
+		if r.err != nil {
+			pe.Failures = append(pe.Failures, &ItemError{Name: n, Err: r.err})
+		}
+		if writeErr == nil {
+			writeErr = writeItemOutput(w, p, n, r.out.Bytes(), r.err)
+		}
+	}
+	wg.Wait()
+	if writeErr != nil {
+		return writeErr
+	}
+	if len(pe.Failures) != 0 {
+		return pe
+	}
+	return nil
+}
+
+func writeItemOutput(w io.Writer, p Parallel, name string, out []byte, err error) error {
+	b := &bytes.Buffer{}
+	if p.Groups {
+		fmt.Fprintf(b, "::group::%s\n", workflowcommand.EscapeData(name))
+	} else {
+		fmt.Fprintf(b, "==> %s\n", name)
+	}
+	b.Write(out)
+	if len(out) != 0 && out[len(out)-1] != '\n' {
+		b.WriteString("\n")
+	}
+	if p.Groups {
+		b.WriteString("::endgroup::\n")
+		if err != nil {
+			fmt.Fprintf(b, "::error title=%s failed::%s\n", workflowcommand.EscapeProperty(name), workflowcommand.EscapeData(err.Error()))
+		}
+	}
+	_, writeErr := w.Write(b.Bytes())
+	return writeErr
+}
+
+type forEachOpts struct {
+	parallel int
+	inputs   []string
+}
+
+func (o *forEachOpts) Flags(fs *flag.FlagSet) {
+	fs.IntVar(&o.parallel, "parallel", 0, "maximum number of inputs to run at once, or 0 for the number of CPUs")
+}
+
+func (o *forEachOpts) Args(al *ArgList) {
+	al.RequiredVariadicFromInput(&o.inputs, "input", 1)
+}
+
+// ForEachCommand returns a command called name that runs the leaf command
+// returned by newLeaf once for each of its INPUT args, using RunParallel. The
+// number run at once is set by its -parallel flag, and INPUT args may be read
+// from stdin or files (see RequiredVariadicArgFromInput).
+//
+// Each input is passed to the leaf as the args returned by args, or as its only
+// arg if args is nil. newLeaf is called for each input, because each run
+// needs its own options; the leaves run as if they were siblings of the
+// returned command, so they inherit its ancestors' middleware and hooks, and
+// the values of their persistent flags as parsed for the returned command.
+// Persistent flags can't be set again in args, because the leaves run
+// concurrently and share their ancestors' persistent options. The leaves have
+// an empty stdin, and their stdout and stderr are buffered per input and
+// written to the command's stdout. When running in GitHub Actions
+// (GITHUB_ACTIONS=true), each input's output is grouped.
+func ForEachCommand(name, desc string, newLeaf func() *Command, args func(input string) []string) *Command {
+	if args == nil {
+		args = func(input string) []string { return []string{input} }
+	}
+	var c *Command
+	c = LeafCommandContext(name, desc, func(ctx context.Context, o *forEachOpts) error {
+		actions, _ := c.LookupEnv("GITHUB_ACTIONS")
+		p := Parallel{Limit: o.parallel, Groups: actions == "true"}
+		inputName := func(input string) string { return input }
+		return RunParallel(ctx, c.stdout, p, o.inputs, inputName, func(ctx context.Context, input string, out io.Writer) error {
+			leaf := newLeaf()
+			leaf.parent = c.parent
+			leaf.runConcurrently = true
+			leaf.SetStdout(out)
+			leaf.SetStderr(out)
+			leaf.SetStdin(strings.NewReader(""))
+			leaf.SetLookupEnv(c.lookupEnv)
+			err := runCLI(ctx, leaf, append([]string{leaf.Name()}, args(input)...))
+			// With groups, errors are written after each group instead.
+			if err != nil && !p.Groups {
+				fmt.Fprintf(out, "Error: %s\n", err)
+			}
+			return err
+		})
+	})
+	return c
+}
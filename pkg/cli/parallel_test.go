@@ -0,0 +1,200 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/composite-action-framework-go/pkg/testhelpers/assert"
+)
+
+func TestRunParallel(t *testing.T) {
+	items := []int{5, 1, 4, 2, 3, 0}
+	var running, maxRunning atomic.Int32
+	buf := &bytes.Buffer{}
+	err := RunParallel(context.Background(), buf, Parallel{Limit: 3}, items, func(i int) string { return fmt.Sprint("item ", i) },
+		func(_ context.Context, i int, out io.Writer) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			// Later items finish first, to check output stays in order.
+			time.Sleep(time.Duration(i) * time.Millisecond)
+			fmt.Fprintf(out, "start %d\n", i)
+			fmt.Fprintf(out, "end %d", i)
+			if i%2 == 1 {
+				return fmt.Errorf("odd number %d", i)
+			}
+			return nil
+		})
+	if got := maxRunning.Load(); got > 3 {
+		t.Errorf("%d items ran at once; want at most 3", got)
+	}
+	want := ""
+	for _, i := range items {
+		want += fmt.Sprintf("==> item %d\nstart %d\nend %d\n", i, i, i)
+	}
+	assert.Equal(t, buf.String(), want)
+	assert.Equal(t, err.Error(), "3 of 6 failed:\n  item 5: odd number 5\n  item 1: odd number 1\n  item 3: odd number 3")
+	var pe *ParallelError
+	if !errors.As(err, &pe) || len(pe.Failures) != 3 {
+		t.Fatalf("got error %#v", err)
+	}
+}
+
+func TestRunParallel_cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	items := []string{"a", "b", "c"}
+	err := RunParallel(ctx, io.Discard, Parallel{Limit: 1}, items, func(s string) string { return s },
+		func(_ context.Context, s string, _ io.Writer) error {
+			cancel()
+			return nil
+		})
+	var pe *ParallelError
+	if !errors.As(err, &pe) {
+		t.Fatalf("got error %v", err)
+	}
+	assert.Equal(t, len(pe.Failures), 2)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v; want context.Canceled", err)
+	}
+}
+
+type pkgTestOpts struct {
+	verbose bool
+	pkg     string
+}
+
+func (o *pkgTestOpts) Flags(fs *flag.FlagSet) {
+	fs.BoolVar(&o.verbose, "v", false, "verbose")
+}
+
+func (o *pkgTestOpts) Args(al *ArgList) {
+	al.Required(&o.pkg, "package")
+}
+
+func TestForEachCommand(t *testing.T) {
+	newLeaf := func() *Command {
+		var c *Command
+		c = LeafCommand("test", "test a package", func(o *pkgTestOpts) error {
+			fmt.Fprintf(c.Stdout(), "testing %s (verbose=%t)\n", o.pkg, o.verbose)
+			if strings.HasPrefix(o.pkg, "bad") {
+				return WithExitCode(errors.New("tests failed"), 3)
+			}
+			return nil
+		})
+		return c
+	}
+	cases := []struct {
+		desc     string
+		args     []string
+		stdin    string
+		actions  bool
+		want     string
+		wantErr  string
+		wantCode int
+	}{
+		{"ok", args("test-all", "-parallel=2", "a", "b"), "", false,
+			"==> a\ntesting a (verbose=true)\n==> b\ntesting b (verbose=true)\n", "", ExitCodeOK},
+		{"stdin", args("test-all", "-"), "a\nb\n", false,
+			"==> a\ntesting a (verbose=true)\n==> b\ntesting b (verbose=true)\n", "", ExitCodeOK},
+		{"failures", args("test-all", "a", "bad1"), "", false,
+			"==> a\ntesting a (verbose=true)\n==> bad1\ntesting bad1 (verbose=true)\nError: tests failed\n",
+			"1 of 2 failed:\n  bad1: tests failed", 3},
+		{"actions", args("test-all", "a", "bad,1"), "", true,
+			"::group::a\ntesting a (verbose=true)\n::endgroup::\n" +
+				"::group::bad,1\ntesting bad,1 (verbose=true)\n::endgroup::\n" +
+				"::error title=bad%2C1 failed::tests failed\n",
+			"1 of 2 failed:\n  bad,1: tests failed", 3},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			root := RootCommand("root", "root command",
+				ForEachCommand("test-all", "test packages", newLeaf, func(pkg string) []string {
+					return []string{"-v", pkg}
+				}),
+			)
+			stdout := &bytes.Buffer{}
+			root.SetStdout(stdout)
+			root.SetStdin(strings.NewReader(c.stdin))
+			root.SetLookupEnv(func(name string) (string, bool) {
+				if name == "GITHUB_ACTIONS" && c.actions {
+					return "true", true
+				}
+				return "", false
+			})
+			err := root.Execute(c.args)
+			assert.Equal(t, stdout.String(), c.want)
+			if c.wantErr == "" && err != nil {
+				t.Fatal(err)
+			}
+			if c.wantErr != "" && (err == nil || err.Error() != c.wantErr) {
+				t.Fatalf("got error %v; want %q", err, c.wantErr)
+			}
+			assert.Equal(t, ExitCode(err), c.wantCode)
+		})
+	}
+}
+
+type forEachLeafOpts struct {
+	input  string
+	global *globalOpts
+}
+
+func (o *forEachLeafOpts) Args(al *ArgList) { al.Required(&o.input, "input") }
+
+func (o *forEachLeafOpts) SetParentOptions(opts any) {
+	if g, ok := opts.(*globalOpts); ok {
+		o.global = g
+	}
+}
+
+// TestForEachCommand_persistentFlags checks that concurrent leaves share their
+// ancestors' persistent flags without racing; run it with -race.
+func TestForEachCommand_persistentFlags(t *testing.T) {
+	newLeaf := func() *Command {
+		var c *Command
+		c = LeafCommand("echo", "echo an input", func(o *forEachLeafOpts) error {
+			fmt.Fprintf(c.Stdout(), "%s debug=%t level=%s\n", o.input, o.global.debug, o.global.level)
+			return nil
+		})
+		return c
+	}
+	inputs := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	var want strings.Builder
+	for _, in := range inputs {
+		fmt.Fprintf(&want, "==> %s\n%s debug=true level=warn\n", in, in)
+	}
+	for _, argv := range [][]string{
+		append(args("-debug", "all", "-level=warn", "-parallel=8"), inputs...),
+		append(args("-debug", "-level=warn", "all", "-parallel=8"), inputs...),
+	} {
+		t.Run(strings.Join(argv[1:4], " "), func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			root := RootCommand("root", "root command",
+				ForEachCommand("all", "echo all inputs", newLeaf, nil),
+			).WithPersistentFlags(&globalOpts{})
+			root.SetStdout(stdout)
+			root.SetLookupEnv(func(string) (string, bool) { return "", false })
+			if err := root.Execute(argv); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, stdout.String(), want.String())
+		})
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"flag"
+	"fmt"
+)
+
+// ParentOptionsSetter can be implemented by a leaf command's options to receive
+// the persistent options of its ancestors (see WithPersistentFlags). It's called
+// once for each ancestor with persistent options, starting at the root, after
+// args are parsed and before Init is called.
+type ParentOptionsSetter interface {
+	SetParentOptions(opts any)
+}
+
+// WithPersistentFlags registers opts' flags on c and all of its descendants,
+// so they can be given at any depth of the command path, e.g. both
+// "mycli -debug sub" and "mycli sub -debug" work. If opts implements Env,
+// its ReadEnv method is called before flags are parsed.
+//
+// Leaf commands access opts by implementing ParentOptionsSetter.
+func (c *Command) WithPersistentFlags(opts Flags) *Command { c.persistent = opts; return c }
+
+// ancestry returns c's path as commands, starting with the root.
+func (c *Command) ancestry() []*Command {
+	var cmds []*Command
+	for curr := c; curr != nil; curr = curr.parent {
+		cmds = append([]*Command{curr}, cmds...)
+	}
+	return cmds
+}
+
+// persistentFlagSources returns the commands whose persistent flags are
+// registered on c's flag set. That's usually c and its ancestors, but commands
+// run concurrently with their siblings only use the values their ancestors'
+// persistent flags already have, as registering them again would race.
+func persistentFlagSources(c *Command) []*Command {
+	if c.runConcurrently {
+		return []*Command{c}
+	}
+	return c.ancestry()
+}
+
+func hasPersistentFlags(c *Command) bool {
+	for _, a := range persistentFlagSources(c) {
+		if a.persistent != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// registerPersistentFlags registers the persistent flags of c and its
+// ancestors on fs, and records their names.
+func registerPersistentFlags(c *Command, fs *flag.FlagSet) {
+	for _, a := range persistentFlagSources(c) {
+		if a.persistent != nil {
+			a.persistent.Flags(fs)
+		}
+	}
+	c.persistentNames = map[string]bool{}
+	fs.VisitAll(func(f *flag.Flag) { c.persistentNames[f.Name] = true })
+}
+
+func readPersistentEnv(c *Command) error {
+	if c.persistent == nil {
+		return nil
+	}
+	return readEnv(c, c.persistent)
+}
+
+// inheritedFlag is a persistent flag value set at or above some level.
+type inheritedFlag struct {
+	// raw is each string the flag was set to, in order.
+	raw []string
+	// final is the flag's value once they were all set.
+	final string
+}
+
+// recordingValue wraps a persistent flag's value while its command's flags are
+// parsed, to record the strings it's set to so they can be replayed at lower
+// levels. Replaying final values instead would corrupt flags that accumulate
+// values, like lists.
+type recordingValue struct {
+	flag.Value
+	raw []string
+	// skip makes Set only mark the flag as set, without setting its value.
+	skip bool
+}
+
+func (v *recordingValue) Set(s string) error {
+	if v.skip {
+		return nil
+	}
+	v.raw = append(v.raw, s)
+	return v.Value.Set(s)
+}
+
+func (v *recordingValue) IsBoolFlag() bool { return isBoolFlag(&flag.Flag{Value: v.Value}) }
+
+// applyInheritedFlags re-applies persistent flag values set at higher levels,
+// since registering flags again resets them to their defaults.
+func applyInheritedFlags(c *Command) error {
+	c.flagSet.VisitAll(func(f *flag.Flag) {
+		if c.persistentNames[f.Name] {
+			f.Value = &recordingValue{Value: f.Value}
+		}
+	})
+	if c.parent == nil || c.runConcurrently {
+		return nil
+	}
+	for name, inherited := range c.parent.persistentValues {
+		f := c.flagSet.Lookup(name)
+		if f == nil {
+			continue
+		}
+		v := f.Value.(*recordingValue)
+		// Values that registering doesn't reset, such as lists defined using
+		// fs.Var, already hold the inherited value, so are only marked as set.
+		v.skip = v.Value.String() == inherited.final
+		for _, s := range inherited.raw {
+			if err := c.flagSet.Set(name, s); err != nil {
+				return fmt.Errorf("invalid value %q for flag -%s: %w", s, name, err)
+			}
+		}
+		v.raw, v.skip = nil, false
+	}
+	return nil
+}
+
+// recordPersistentFlags records the persistent flag values set at this level
+// or above, so they can be passed down to subcommands.
+func recordPersistentFlags(c *Command) {
+	c.persistentValues = map[string]inheritedFlag{}
+	if c.parent != nil {
+		for k, v := range c.parent.persistentValues {
+			c.persistentValues[k] = v
+		}
+	}
+	if c.flagSet == nil {
+		return
+	}
+	c.flagSet.VisitAll(func(f *flag.Flag) {
+		v, ok := f.Value.(*recordingValue)
+		if !ok || len(v.raw) == 0 {
+			return
+		}
+		inherited := c.persistentValues[f.Name]
+		c.persistentValues[f.Name] = inheritedFlag{
+			raw:   append(append([]string{}, inherited.raw...), v.raw...),
+			final: v.String(),
+		}
+	})
+}
+
+func setParentOptions(c *Command) {
+	if c.parentOptsSetter == nil {
+		return
+	}
+	for _, a := range c.ancestry() {
+		if a.persistent != nil {
+			c.parentOptsSetter.SetParentOptions(a.persistent)
+		}
+	}
+}
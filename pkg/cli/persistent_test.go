@@ -0,0 +1,87 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type globalOpts struct {
+	debug bool
+	level string
+	tags  listFlag
+}
+
+func (o *globalOpts) Flags(fs *flag.FlagSet) {
+	fs.BoolVar(&o.debug, "debug", false, "debug")
+	fs.StringVar(&o.level, "level", "info", "log level")
+	fs.Var(&o.tags, "tag", "tag (repeatable)")
+}
+
+type persistentLeafOpts struct {
+	name   string
+	global *globalOpts
+}
+
+func (o *persistentLeafOpts) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&o.name, "name", "", "name")
+}
+
+func (o *persistentLeafOpts) SetParentOptions(opts any) {
+	if g, ok := opts.(*globalOpts); ok {
+		o.global = g
+	}
+}
+
+func TestWithPersistentFlags(t *testing.T) {
+	cases := []struct {
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{args("sub", "leaf"), "false info ", ""},
+		{args("-debug", "sub", "leaf"), "true info ", ""},
+		{args("sub", "-debug", "leaf"), "true info ", ""},
+		{args("sub", "leaf", "-debug"), "true info ", ""},
+		{args("-level", "warn", "sub", "leaf", "-debug", "-name", "x"), "true warn x", ""},
+		{args("-level", "warn", "sub", "-level", "error", "leaf"), "false error ", ""},
+		{args("-tag", "a", "sub", "leaf"), "false info  [a]", ""},
+		{args("-tag", "a", "sub", "-tag", "b", "leaf", "-tag", "c"), "false info  [a b c]", ""},
+		{args("-tag", "a", "-level", "warn", "sub", "leaf", "-tag", "b"), "false warn  [a b]", ""},
+		{args("sub", "leaf", "-nope"), "", "flag provided but not defined: -nope"},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(strings.Join(c.args[1:], " "), func(t *testing.T) {
+			var got string
+			root := RootCommand("root", "root command",
+				RootCommand("sub", "sub command",
+					LeafCommand("leaf", "leaf command", func(o *persistentLeafOpts) error {
+						got = fmt.Sprintf("%t %s %s", o.global.debug, o.global.level, o.name)
+						if len(o.global.tags) != 0 {
+							got += fmt.Sprintf(" %v", []string(o.global.tags))
+						}
+						return nil
+					}),
+				),
+			).WithPersistentFlags(&globalOpts{})
+			err := root.Execute(c.args)
+			if c.wantErr != "" {
+				if err == nil || err.Error() != c.wantErr {
+					t.Fatalf("got error %v; want %q", err, c.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("got %q; want %q", got, c.want)
+			}
+		})
+	}
+}
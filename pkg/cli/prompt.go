@@ -0,0 +1,188 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// SecretInputs can be implemented by options, to list the names of flags and
+// args whose values are secret. When prompted for (see WithPrompting) these
+// are read without being echoed to the terminal.
+type SecretInputs interface {
+	SecretInputs() []string
+}
+
+// WithPrompting makes c and its subcommands prompt for the values of missing
+// required args, and flags made required by RequiredFlags, rather than failing.
+// Prompting only happens when the command's stdin is a terminal, and never
+// when the CI or GITHUB_ACTIONS environment variables are set, so it's safe to
+// enable for commands that are also run non-interactively.
+func (c *Command) WithPrompting() *Command { c.prompting = true; return c }
+
+// isTerminal and readPassword are variables so tests can replace them.
+var (
+	isTerminal = func(r io.Reader) bool {
+		f, ok := r.(*os.File)
+		return ok && term.IsTerminal(int(f.Fd()))
+	}
+	readPassword = func(r io.Reader) (string, error) {
+		f, ok := r.(*os.File)
+		if !ok {
+			return "", errors.New("cannot read secret input from a non-terminal")
+		}
+		b, err := term.ReadPassword(int(f.Fd()))
+		return string(b), err
+	}
+)
+
+// shouldPrompt reports whether c should prompt for missing values.
+func shouldPrompt(c *Command) bool {
+	enabled := false
+	for _, a := range c.ancestry() {
+		enabled = enabled || a.prompting
+	}
+	if !enabled {
+		return false
+	}
+	for _, name := range []string{"CI", "GITHUB_ACTIONS"} {
+		if v, _ := c.LookupEnv(name); v != "" {
+			return false
+		}
+	}
+	return isTerminal(c.stdin)
+}
+
+// prompter reads values from the command's stdin. Values are read without
+// buffering, so that secret values read by readPassword and other values are
+// read from the same stream in order, and input typed ahead isn't lost.
+type prompter struct {
+	c       *Command
+	secrets map[string]bool
+}
+
+func (c *Command) getPrompter() *prompter {
+	if c.prompter == nil {
+		p := &prompter{c: c, secrets: map[string]bool{}}
+		if s, ok := c.opts.(SecretInputs); ok {
+			for _, name := range s.SecretInputs() {
+				p.secrets[name] = true
+				p.secrets[strings.ToUpper(name)] = true
+			}
+		}
+		c.prompter = p
+	}
+	return c.prompter
+}
+
+// prompt asks for the value called name until a non-empty value is entered.
+// If the input ends first, it returns io.ErrUnexpectedEOF.
+func (p *prompter) prompt(name, label string) (string, error) {
+	for {
+		fmt.Fprintf(p.c.stderr, "%s: ", label)
+		var value string
+		var err error
+		if p.secrets[name] {
+			value, err = readPassword(p.c.stdin)
+			fmt.Fprintln(p.c.stderr)
+		} else {
+			value, err = readLine(p.c.stdin)
+		}
+		if err == io.EOF {
+			return "", io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return "", err
+		}
+		if value = strings.TrimRight(value, "\r\n"); value != "" {
+			return value, nil
+		}
+	}
+}
+
+// readLine reads a line from r one byte at a time, so that nothing after the
+// line is consumed. It returns io.EOF only if r is empty.
+func readLine(r io.Reader) (string, error) {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := r.Read(b)
+		if n == 1 {
+			if b[0] == '\n' {
+				return string(line), nil
+			}
+			line = append(line, b[0])
+		}
+		if err == io.EOF && len(line) != 0 {
+			return string(line), nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// promptForArgs returns args with values prompted for any missing required args
+// appended.
+func promptForArgs(c *Command, al ArgList, args []string) ([]string, error) {
+	p := c.getPrompter()
+	for i, a := range al {
+		if !a.required {
+			break
+		}
+		if !a.variadic {
+			if i < len(args) {
+				continue
+			}
+			value, err := p.prompt(a.name, a.displayName(""))
+			if err != nil {
+				return nil, fmt.Errorf("reading %s: %w", a.name, err)
+			}
+			args = append(args, value)
+			continue
+		}
+		for n := len(args) - i; n < a.minVals; n++ {
+			value, err := p.prompt(a.name, a.displayName(fmt.Sprint(n)))
+			if err != nil {
+				return nil, fmt.Errorf("reading %s: %w", a.name, err)
+			}
+			args = append(args, value)
+		}
+	}
+	return args, nil
+}
+
+// promptForFlags sets values prompted for any flags required by RequiredFlags
+// constraints that aren't already set.
+func promptForFlags(c *Command) error {
+	set := map[string]bool{}
+	c.flagSet.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	for _, fc := range flagConstraints(c) {
+		if fc.kind != requiredConstraint {
+			continue
+		}
+		for _, name := range fc.flags {
+			f := c.flagSet.Lookup(name)
+			if f == nil || set[name] {
+				continue
+			}
+			value, err := c.getPrompter().prompt(name, fmt.Sprintf("-%s (%s)", name, c.flagUsages[name]))
+			if err != nil {
+				return fmt.Errorf("reading -%s: %w", name, err)
+			}
+			if err := setFlag(c.flagSet, name, value); err != nil {
+				return err
+			}
+			set[name] = true
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/composite-action-framework-go/pkg/testhelpers/assert"
+)
+
+type promptOpts struct {
+	user, token, name string
+	files             []string
+}
+
+func (o *promptOpts) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&o.user, "user", "", "user name")
+	fs.StringVar(&o.token, "token", "", "API token")
+}
+
+func (o *promptOpts) FlagConstraints() []FlagConstraint {
+	return []FlagConstraint{RequiredFlags("user", "token")}
+}
+
+func (o *promptOpts) Args(al *ArgList) {
+	al.Required(&o.name, "name")
+	al.RequiredVariadic(&o.files, "file", 2)
+}
+
+func (o *promptOpts) SecretInputs() []string { return []string{"token"} }
+
+func TestWithPrompting(t *testing.T) {
+	defer func(t func(io.Reader) bool, p func(io.Reader) (string, error)) {
+		isTerminal, readPassword = t, p
+	}(isTerminal, readPassword)
+	// Read secrets from the same stream as other values, to check that they're
+	// read in order.
+	readPassword = readLine
+
+	cases := []struct {
+		desc       string
+		args       []string
+		stdin      string
+		tty        bool
+		env        string
+		want       string
+		wantPrompt string
+		wantErr    string
+	}{
+		{"all prompted", args("leaf"), "bob\ns3cret\nx\n\nf1\nf2", true, "",
+			"bob s3cret x [f1 f2]", "-user (user name): -token (API token): \nNAME: FILE0: FILE0: FILE1: ", ""},
+		{"some prompted", args("leaf", "-user=al", "x", "f1"), "s3cret\nf2\n", true, "",
+			"al s3cret x [f1 f2]", "-token (API token): \nFILE1: ", ""},
+		{"none prompted", args("leaf", "-user=al", "-token=t", "x", "f1", "f2"), "", true, "",
+			"al t x [f1 f2]", "", ""},
+		{"eof", args("leaf", "-user=al", "-token=t"), "", true, "",
+			"", "NAME: ", "reading NAME: unexpected EOF"},
+		{"not a terminal", args("leaf", "-token=t"), "bob\n", false, "",
+			"", "", "required flag -user not set"},
+		{"in CI", args("leaf", "-token=t"), "bob\n", true, "CI",
+			"", "", "required flag -user not set"},
+		{"in actions", args("leaf", "-token=t"), "bob\n", true, "GITHUB_ACTIONS",
+			"", "", "required flag -user not set"},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			isTerminal = func(io.Reader) bool { return c.tty }
+			var got string
+			root := RootCommand("root", "root command",
+				LeafCommand("leaf", "leaf command", func(o *promptOpts) error {
+					got = fmt.Sprintf("%s %s %s %v", o.user, o.token, o.name, o.files)
+					return nil
+				}).WithFlagEnv(), // Flag env vars shouldn't appear in prompts.
+			).WithPrompting()
+			stderr := &bytes.Buffer{}
+			root.SetStdin(strings.NewReader(c.stdin))
+			root.SetStderr(stderr)
+			root.SetLookupEnv(func(name string) (string, bool) {
+				if name == c.env {
+					return "true", true
+				}
+				return "", false
+			})
+			err := root.Execute(c.args)
+			if c.wantErr != "" {
+				if err == nil || err.Error() != c.wantErr {
+					t.Fatalf("got error %v; want %q", err, c.wantErr)
+				}
+				if !errors.Is(err, io.ErrUnexpectedEOF) {
+					return
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, got, c.want)
+			if !strings.HasPrefix(stderr.String(), c.wantPrompt) {
+				t.Errorf("got prompts %q; want %q", stderr.String(), c.wantPrompt)
+			}
+		})
+	}
+}
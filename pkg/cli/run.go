@@ -3,44 +3,60 @@
 
 package cli
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"flag"
+)
 
-func runCLI(c *Command, args []string) error {
+func runCLI(ctx context.Context, c *Command, args []string) error {
 	if helpFunc := helpRequested(c, args); helpFunc != nil {
 		return helpFunc()
 	}
+	warnDeprecated(c)
+	c.prompter = nil
 	if err := parseEnv(c); err != nil {
-		return err
+		return runtimeError(err)
+	}
+	if err := readPersistentEnv(c); err != nil {
+		return runtimeError(err)
 	}
 	subArgs, err := parseFlags(c, args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return c.printHelp(c.stdout)
+	}
 	if err != nil {
-		return err
+		return usageError(c, err)
 	}
 	if len(subArgs) == 0 {
-		return run(c, nil)
+		return run(ctx, c, nil)
 	}
 	sub := subArgs[0]
 	if len(c.Subcommands()) == 0 {
-		return run(c, subArgs)
+		return run(ctx, c, subArgs)
 	}
-	sc, ok := getSubCommand(c, sub)
-	if !ok {
-		return fmt.Errorf("subcommand %q not found", sub)
+	sc, err := findSubcommand(c, sub)
+	if err != nil {
+		return usageError(c, err)
 	}
-	return runCLI(sc, subArgs)
+	return runCLI(ctx, sc, subArgs)
 }
 
-func run(c *Command, args []string) error {
-	if c.Run() == nil {
+func run(ctx context.Context, c *Command, args []string) error {
+	if c.run == nil {
 		return ErrNotImplemented
 	}
+	if s := c.stdoutSet; s != nil {
+		s.SetStdout(c.stdout)
+	}
 	if err := parseArgs(c, args); err != nil {
-		return err
+		return usageError(c, err)
 	}
+	setParentOptions(c)
 	if err := initOpts(c); err != nil {
-		return err
+		return runtimeError(err)
 	}
-	return c.Run()()
+	return runtimeError(runWithHooks(ctx, c))
 }
 
 func helpRequested(c *Command, args []string) func() error {
@@ -0,0 +1,78 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// DefaultGracePeriod is how long ExecuteContext waits for a command to return
+// after cancelling its context, unless changed using WithGracePeriod.
+const DefaultGracePeriod = 10 * time.Second
+
+// exit is swapped out in tests.
+var exit = os.Exit
+
+// WithGracePeriod sets how long ExecuteContext waits for the running command
+// to return after receiving SIGINT or SIGTERM before forcing the process to exit.
+// It should be called on the root command.
+func (c *Command) WithGracePeriod(d time.Duration) *Command { c.gracePeriod = d; return c }
+
+// ExecuteContext is like Execute, except that ctx is passed to commands created
+// with LeafCommandContext.
+//
+// When the process receives SIGINT or SIGTERM, ctx is cancelled, and the
+// running command has the grace period (see WithGracePeriod) to return. If it
+// doesn't return in time, or a second signal is received, the process exits
+// with status 128 plus the signal number.
+func (c *Command) ExecuteContext(ctx context.Context, args []string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	done := make(chan error, 1)
+	go func() { done <- runCLI(ctx, c, args) }()
+
+	var sig os.Signal
+	select {
+	case err := <-done:
+		return err
+	case sig = <-sigs:
+	}
+
+	grace := c.gracePeriod
+	if grace == 0 {
+		grace = DefaultGracePeriod
+	}
+	fmt.Fprintf(c.stderr, "received %s; cancelling (waiting up to %s)\n", sig, grace)
+	cancel()
+
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		fmt.Fprintf(c.stderr, "command did not exit within %s; forcing exit\n", grace)
+	case sig = <-sigs:
+		fmt.Fprintf(c.stderr, "received %s again; forcing exit\n", sig)
+	}
+	exit(signalExitCode(sig))
+	return ctx.Err()
+}
+
+func signalExitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 1
+}
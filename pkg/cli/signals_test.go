@@ -0,0 +1,69 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !windows
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// interruptSelf is called from a separate goroutine, so it can't use t.Fatal.
+func interruptSelf(t *testing.T) {
+	t.Helper()
+	if err := syscall.Kill(os.Getpid(), syscall.SIGINT); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestExecuteContext_cancelled(t *testing.T) {
+	started := make(chan struct{})
+	root := RootCommand("root", "root command",
+		LeafCommandContext("leaf", "leaf command", func(ctx context.Context, _ None) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	)
+	root.SetStderr(&bytes.Buffer{})
+	go func() {
+		<-started
+		interruptSelf(t)
+	}()
+	err := root.ExecuteContext(context.Background(), args("leaf"))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v; want %v", err, context.Canceled)
+	}
+}
+
+func TestExecuteContext_forcedExit(t *testing.T) {
+	var exitCode int
+	exit = func(code int) { exitCode = code }
+	defer func() { exit = os.Exit }()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	root := RootCommand("root", "root command",
+		LeafCommandContext("leaf", "leaf command", func(context.Context, None) error {
+			close(started)
+			<-release
+			return nil
+		}),
+	).WithGracePeriod(10 * time.Millisecond)
+	root.SetStderr(&bytes.Buffer{})
+	go func() {
+		<-started
+		interruptSelf(t)
+	}()
+	_ = root.ExecuteContext(context.Background(), args("leaf"))
+	if want := 128 + int(syscall.SIGINT); exitCode != want {
+		t.Fatalf("got exit code %d; want %d", exitCode, want)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/composite-action-framework-go/pkg/internal/workflowcommand"
+)
+
+// WithHidden hides c from its parent's help, docs, shell completions and
+// subcommand suggestions. It can still be run by its full name.
+func (c *Command) WithHidden() *Command { c.hidden = true; return c }
+
+// WithDeprecated marks c as deprecated. It's still listed in help, but a warning
+// is written to stderr whenever it's run. If replacement isn't empty, it's the
+// path of the command to use instead, e.g. "mycli new-command", and the warning
+// names it.
+//
+// When running in GitHub Actions (GITHUB_ACTIONS=true) the warning is also
+// written as a workflow warning command, so it appears as an annotation on the
+// workflow run. This is written to stderr too, which the runner also reads
+// workflow commands from, so as not to corrupt the command's output.
+func (c *Command) WithDeprecated(replacement string) *Command {
+	c.deprecated, c.replacement = true, replacement
+	return c
+}
+
+// WithExperimental marks c as experimental in help and docs, meaning it may
+// change or be removed without warning.
+func (c *Command) WithExperimental() *Command { c.experimental = true; return c }
+
+func (c *Command) Hidden() bool       { return c.hidden }
+func (c *Command) Deprecated() bool   { return c.deprecated }
+func (c *Command) Experimental() bool { return c.experimental }
+
+// visibleSubcommands returns c's subcommands that aren't hidden.
+func visibleSubcommands(c *Command) []*Command {
+	var subs []*Command
+	for _, s := range c.subs {
+		if !s.hidden {
+			subs = append(subs, s)
+		}
+	}
+	return subs
+}
+
+// stateLabel returns a label describing c's state for use in subcommand
+// listings, or an empty string.
+func stateLabel(c *Command) string {
+	switch {
+	case c.deprecated:
+		return "[deprecated] "
+	case c.experimental:
+		return "[experimental] "
+	}
+	return ""
+}
+
+// stateNotice returns a sentence describing c's state for use in its own help,
+// or an empty string.
+func stateNotice(c *Command) string {
+	switch {
+	case c.deprecated:
+		return deprecationMessage(c)
+	case c.experimental:
+		return fmt.Sprintf("Command %q is experimental and may change or be removed.", c.PathString())
+	}
+	return ""
+}
+
+func deprecationMessage(c *Command) string {
+	if c.replacement == "" {
+		return fmt.Sprintf("Command %q is deprecated and will be removed.", c.PathString())
+	}
+	return fmt.Sprintf("Command %q is deprecated; use %q instead.", c.PathString(), c.replacement)
+}
+
+// warnDeprecated writes a warning to stderr if c is deprecated, and also a
+// workflow command when running in GitHub Actions.
+func warnDeprecated(c *Command) {
+	if !c.deprecated {
+		return
+	}
+	msg := deprecationMessage(c)
+	fmt.Fprintf(c.stderr, "Warning: %s\n", msg)
+	if v, _ := c.LookupEnv("GITHUB_ACTIONS"); v == "true" {
+		fmt.Fprintf(c.stderr, "::warning title=Deprecated command::%s\n", workflowcommand.EscapeData(msg))
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/composite-action-framework-go/pkg/testhelpers/assert"
+)
+
+func stateTestRoot() *Command {
+	leaf := func(name string) *Command {
+		return LeafCommand(name, name+" things", func(None) error { return nil })
+	}
+	return RootCommand("root", "root command",
+		leaf("new"),
+		leaf("old").WithDeprecated("root new"),
+		leaf("older").WithDeprecated(""),
+		leaf("beta").WithExperimental(),
+		leaf("secret").WithHidden(),
+	)
+}
+
+func TestCommandStates_help(t *testing.T) {
+	root := stateTestRoot()
+	stdout := &bytes.Buffer{}
+	root.SetStdout(stdout)
+	if err := root.Execute(args("-h")); err != nil {
+		t.Fatal(err)
+	}
+	want := `root - root command
+
+Usage:
+
+  root SUBCOMMAND
+
+Subcommands:
+
+  new    new things
+  old    [deprecated] old things
+  older  [deprecated] older things
+  beta   [experimental] beta things
+`
+	assert.Equal(t, stdout.String(), want)
+
+	stdout.Reset()
+	if err := root.Execute(args("old", "-h")); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, stdout.String(), "old - old things\n\nCommand \"root old\" is deprecated; use \"root new\" instead.\n\nUsage:\n\n  root old\n")
+}
+
+func TestCommandStates_run(t *testing.T) {
+	cases := []struct {
+		args       []string
+		actions    bool
+		wantStderr string
+	}{
+		{args("new"), false, ""},
+		{args("secret"), false, ""},
+		{args("beta"), false, ""},
+		{args("old"), false, "Warning: Command \"root old\" is deprecated; use \"root new\" instead.\n"},
+		{args("older"), false, "Warning: Command \"root older\" is deprecated and will be removed.\n"},
+		{args("old"), true, "Warning: Command \"root old\" is deprecated; use \"root new\" instead.\n" +
+			"::warning title=Deprecated command::Command \"root old\" is deprecated; use \"root new\" instead.\n"},
+	}
+	for _, c := range cases {
+		root := stateTestRoot()
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		root.SetStdout(stdout)
+		root.SetStderr(stderr)
+		root.SetLookupEnv(func(name string) (string, bool) {
+			if name == "GITHUB_ACTIONS" && c.actions {
+				return "true", true
+			}
+			return "", false
+		})
+		if err := root.Execute(c.args); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, stderr.String(), c.wantStderr)
+		assert.Equal(t, stdout.String(), "")
+	}
+
+	root := stateTestRoot()
+	root.SetStderr(&bytes.Buffer{})
+	err := root.Execute(args("secre"))
+	want := `subcommand "secre" not found (available: new, old, older, beta)`
+	if err == nil || err.Error() != want {
+		t.Errorf("got error %v; want %q", err, want)
+	}
+}
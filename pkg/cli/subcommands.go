@@ -0,0 +1,121 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"sort"
+	"strings"
+)
+
+// WithAliases adds alternative names that c can be invoked by.
+func (c *Command) WithAliases(aliases ...string) *Command {
+	c.aliases = append(c.aliases, aliases...)
+	return c
+}
+
+func (c *Command) Aliases() []string { return c.aliases }
+
+// WithPrefixMatching allows subcommands of c and its descendants to be invoked
+// by any unique prefix of their name or one of their aliases, e.g. "st" for
+// "status" as long as there's no other subcommand starting with "st".
+func (c *Command) WithPrefixMatching() *Command { c.prefixMatching = true; return c }
+
+func prefixMatching(c *Command) bool {
+	for _, a := range c.ancestry() {
+		if a.prefixMatching {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Command) names() []string { return append([]string{c.name}, c.aliases...) }
+
+// findSubcommand returns the subcommand of parent called name, or else a
+// SubcommandNotFoundError. Hidden subcommands are only found by exact name.
+func findSubcommand(parent *Command, name string) (*Command, error) {
+	for _, s := range parent.Subcommands() {
+		for _, n := range s.names() {
+			if n == name {
+				return s, nil
+			}
+		}
+	}
+	var matches []*Command
+	if prefixMatching(parent) {
+		for _, s := range visibleSubcommands(parent) {
+			for _, n := range s.names() {
+				if strings.HasPrefix(n, name) {
+					matches = append(matches, s)
+					break
+				}
+			}
+		}
+		if len(matches) == 1 {
+			return matches[0], nil
+		}
+	}
+	err := &SubcommandNotFoundError{Name: name}
+	for _, s := range visibleSubcommands(parent) {
+		err.Available = append(err.Available, s.Name())
+	}
+	if len(matches) > 1 {
+		for _, m := range matches {
+			err.Suggestions = append(err.Suggestions, m.Name())
+		}
+		return nil, err
+	}
+	err.Suggestions = suggestSubcommands(parent, name)
+	return nil, err
+}
+
+// suggestSubcommands returns the names of parent's subcommands that are
+// within a small edit distance of name, closest first.
+func suggestSubcommands(parent *Command, name string) []string {
+	maxDist := max(2, len(name)/3)
+	type suggestion struct {
+		name string
+		dist int
+	}
+	var suggestions []suggestion
+	for _, s := range visibleSubcommands(parent) {
+		best := -1
+		for _, n := range s.names() {
+			if d := levenshtein(name, n); best == -1 || d < best {
+				best = d
+			}
+		}
+		if best <= maxDist {
+			suggestions = append(suggestions, suggestion{s.Name(), best})
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool { return suggestions[i].dist < suggestions[j].dist })
+	names := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		names[i] = s.name
+	}
+	return names
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(br)]
+}
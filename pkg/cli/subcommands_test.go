@@ -0,0 +1,77 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindSubcommand(t *testing.T) {
+	cases := []struct {
+		args           []string
+		prefixMatching bool
+		want           string
+		wantErr        string
+	}{
+		{args("status"), false, "status", ""},
+		{args("st"), false, "status", ""},
+		{args("sta"), false, "", `subcommand "sta" not found; did you mean "status" or "start"? (available: start, status, delete)`},
+		{args("sta"), true, "", `subcommand "sta" not found; did you mean "start" or "status"? (available: start, status, delete)`},
+		{args("stat"), true, "status", ""},
+		{args("rm"), false, "delete", ""},
+		{args("del"), true, "delete", ""},
+		{args("stauts"), false, "", `subcommand "stauts" not found; did you mean "start" or "status"? (available: start, status, delete)`},
+		{args("dleete"), true, "", `subcommand "dleete" not found; did you mean "delete"? (available: start, status, delete)`},
+		{args("xyz"), false, "", `subcommand "xyz" not found (available: start, status, delete)`},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(strings.Join(c.args[1:], " "), func(t *testing.T) {
+			var got string
+			leaf := func(name string) *Command {
+				return LeafCommand(name, name, func(None) error { got = name; return nil })
+			}
+			root := RootCommand("root", "root command",
+				leaf("start"),
+				leaf("status").WithAliases("st"),
+				leaf("delete").WithAliases("rm", "remove"),
+			)
+			if c.prefixMatching {
+				root.WithPrefixMatching()
+			}
+			err := root.Execute(c.args)
+			if c.wantErr != "" {
+				if err == nil || err.Error() != c.wantErr {
+					t.Fatalf("got error %v; want %q", err, c.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("ran %q; want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"status", "stauts", 2},
+		{"héllo", "hello", 1},
+	}
+	for _, c := range cases {
+		if got := levenshtein(c.a, c.b); got != c.want {
+			t.Errorf("levenshtein(%q, %q) = %d; want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+
+	"github.com/hashicorp/composite-action-framework-go/pkg/json"
+)
+
+// EnvTag is the struct tag used to name the environment variable a Context
+// field is read from.
+const EnvTag = "env"
+
+// Context holds the default environment variables set by the actions runner,
+// and gives access to the event payload that triggered the workflow.
+// It implements cli.Env so it can be embedded in a command's options.
+//
+// See https://docs.github.com/en/actions/learn-github-actions/variables#default-environment-variables
+type Context struct {
+	CI                bool   `env:"CI"`
+	Action            string `env:"GITHUB_ACTION"`
+	ActionPath        string `env:"GITHUB_ACTION_PATH"`
+	ActionRepository  string `env:"GITHUB_ACTION_REPOSITORY"`
+	Actions           bool   `env:"GITHUB_ACTIONS"`
+	Actor             string `env:"GITHUB_ACTOR"`
+	ActorID           int64  `env:"GITHUB_ACTOR_ID"`
+	APIURL            string `env:"GITHUB_API_URL"`
+	BaseRef           string `env:"GITHUB_BASE_REF"`
+	EnvPath           string `env:"GITHUB_ENV"`
+	EventName         string `env:"GITHUB_EVENT_NAME"`
+	EventPath         string `env:"GITHUB_EVENT_PATH"`
+	GraphQLURL        string `env:"GITHUB_GRAPHQL_URL"`
+	HeadRef           string `env:"GITHUB_HEAD_REF"`
+	Job               string `env:"GITHUB_JOB"`
+	OutputPath        string `env:"GITHUB_OUTPUT"`
+	Path              string `env:"GITHUB_PATH"`
+	Ref               string `env:"GITHUB_REF"`
+	RefName           string `env:"GITHUB_REF_NAME"`
+	RefProtected      bool   `env:"GITHUB_REF_PROTECTED"`
+	RefType           string `env:"GITHUB_REF_TYPE"`
+	Repository        string `env:"GITHUB_REPOSITORY"`
+	RepositoryID      int64  `env:"GITHUB_REPOSITORY_ID"`
+	RepositoryOwner   string `env:"GITHUB_REPOSITORY_OWNER"`
+	RepositoryOwnerID int64  `env:"GITHUB_REPOSITORY_OWNER_ID"`
+	RetentionDays     int    `env:"GITHUB_RETENTION_DAYS"`
+	RunAttempt        int    `env:"GITHUB_RUN_ATTEMPT"`
+	RunID             int64  `env:"GITHUB_RUN_ID"`
+	RunNumber         int    `env:"GITHUB_RUN_NUMBER"`
+	ServerURL         string `env:"GITHUB_SERVER_URL"`
+	SHA               string `env:"GITHUB_SHA"`
+	StepSummaryPath   string `env:"GITHUB_STEP_SUMMARY"`
+	TriggeringActor   string `env:"GITHUB_TRIGGERING_ACTOR"`
+	Workflow          string `env:"GITHUB_WORKFLOW"`
+	WorkflowRef       string `env:"GITHUB_WORKFLOW_REF"`
+	WorkflowSHA       string `env:"GITHUB_WORKFLOW_SHA"`
+	Workspace         string `env:"GITHUB_WORKSPACE"`
+	RunnerArch        string `env:"RUNNER_ARCH"`
+	RunnerName        string `env:"RUNNER_NAME"`
+	RunnerOS          string `env:"RUNNER_OS"`
+	RunnerTemp        string `env:"RUNNER_TEMP"`
+	RunnerToolCache   string `env:"RUNNER_TOOL_CACHE"`
+	RunnerDebug       bool   `env:"RUNNER_DEBUG"`
+	eventPayload      []byte
+	envLookup
+}
+
+func (c *Context) ReadEnv() error {
+	rv := reflect.ValueOf(c).Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		name := rt.Field(i).Tag.Get(EnvTag)
+		if name == "" || name == "-" {
+			continue
+		}
+		value := c.getenv(name)
+		if value == "" {
+			continue
+		}
+		if err := setEnvValue(rv.Field(i), value); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// setEnvValue is like setValue, except that bools are parsed by
+// strconv.ParseBool, because the runner sets some to "1" rather than "true".
+func setEnvValue(v reflect.Value, value string) error {
+	if v.Kind() != reflect.Bool {
+		return setValue(v, value)
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	v.SetBool(b)
+	return nil
+}
+
+// RepositoryURL returns the web URL of the repository the workflow is running in.
+func (c *Context) RepositoryURL() string {
+	return fmt.Sprintf("%s/%s", c.ServerURL, c.Repository)
+}
+
+// RunURL returns the web URL of the current workflow run.
+func (c *Context) RunURL() string {
+	return fmt.Sprintf("%s/actions/runs/%d", c.RepositoryURL(), c.RunID)
+}
+
+// Event returns the event payload read from $GITHUB_EVENT_PATH, decoded into the type matching EventName,
+// i.e. one of *PushEvent, *PullRequestEvent, *WorkflowDispatchEvent or
+// *ReleaseEvent. Other events are decoded into a map[string]any.
+func (c *Context) Event() (any, error) {
+	switch c.EventName {
+	case "push":
+		return c.PushEvent()
+	case "pull_request", "pull_request_target":
+		return c.PullRequestEvent()
+	case "workflow_dispatch":
+		return c.WorkflowDispatchEvent()
+	case "release":
+		return c.ReleaseEvent()
+	}
+	return readEvent[map[string]any](c)
+}
+
+func (c *Context) PushEvent() (*PushEvent, error) {
+	if err := c.assertEvent("push"); err != nil {
+		return nil, err
+	}
+	return readEvent[*PushEvent](c)
+}
+
+func (c *Context) PullRequestEvent() (*PullRequestEvent, error) {
+	if err := c.assertEvent("pull_request", "pull_request_target"); err != nil {
+		return nil, err
+	}
+	return readEvent[*PullRequestEvent](c)
+}
+
+func (c *Context) WorkflowDispatchEvent() (*WorkflowDispatchEvent, error) {
+	if err := c.assertEvent("workflow_dispatch"); err != nil {
+		return nil, err
+	}
+	return readEvent[*WorkflowDispatchEvent](c)
+}
+
+func (c *Context) ReleaseEvent() (*ReleaseEvent, error) {
+	if err := c.assertEvent("release"); err != nil {
+		return nil, err
+	}
+	return readEvent[*ReleaseEvent](c)
+}
+
+func (c *Context) assertEvent(names ...string) error {
+	for _, n := range names {
+		if c.EventName == n {
+			return nil
+		}
+	}
+	return fmt.Errorf("event is %q, not %q", c.EventName, names[0])
+}
+
+// readEvent decodes the event payload, which is read the first time it's
+// needed so that commands that don't use it don't fail if it's missing.
+func readEvent[T any](c *Context) (T, error) {
+	var zero T
+	if c.eventPayload == nil {
+		if c.EventPath == "" {
+			return zero, fmt.Errorf("no event payload; GITHUB_EVENT_PATH not set")
+		}
+		b, err := os.ReadFile(c.EventPath)
+		if err != nil {
+			return zero, fmt.Errorf("reading event payload: %w", err)
+		}
+		c.eventPayload = b
+	}
+	return json.ReadBytes[T](c.eventPayload, json.AllowUnknownFields())
+}
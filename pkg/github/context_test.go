@@ -0,0 +1,113 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestContext_ReadEnv(t *testing.T) {
+	eventPath := filepath.Join(t.TempDir(), "event.json")
+	payload := `{
+		"ref": "refs/heads/main",
+		"after": "abc123",
+		"head_commit": {"id": "abc123", "message": "hello"},
+		"repository": {"full_name": "hashicorp/blah", "default_branch": "main"},
+		"unknown_field": true
+	}`
+	if err := os.WriteFile(eventPath, []byte(payload), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_EVENT_NAME", "push")
+	t.Setenv("GITHUB_EVENT_PATH", eventPath)
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_RUN_ID", "1234")
+	t.Setenv("GITHUB_SERVER_URL", "https://github.com")
+	t.Setenv("GITHUB_REPOSITORY", "hashicorp/blah")
+	t.Setenv("RUNNER_DEBUG", "1")
+
+	c := &Context{}
+	if err := c.ReadEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if !c.Actions || !c.RunnerDebug || c.RunID != 1234 {
+		t.Errorf("got Actions=%t RunnerDebug=%t RunID=%d", c.Actions, c.RunnerDebug, c.RunID)
+	}
+	if got, want := c.RunURL(), "https://github.com/hashicorp/blah/actions/runs/1234"; got != want {
+		t.Errorf("got RunURL() = %q; want %q", got, want)
+	}
+	e, err := c.Event()
+	if err != nil {
+		t.Fatal(err)
+	}
+	push, ok := e.(*PushEvent)
+	if !ok {
+		t.Fatalf("got event type %T; want *PushEvent", e)
+	}
+	if push.After != "abc123" || push.HeadCommit.Message != "hello" || push.Repository.DefaultBranch != "main" {
+		t.Errorf("unexpected push event %+v", push)
+	}
+	if _, err := c.ReleaseEvent(); err == nil {
+		t.Errorf("got nil error reading release event from push")
+	}
+}
+
+func TestContext_ReadEnv_eventReadLazily(t *testing.T) {
+	eventPath := filepath.Join(t.TempDir(), "missing.json")
+	t.Setenv("GITHUB_EVENT_NAME", "push")
+	t.Setenv("GITHUB_EVENT_PATH", eventPath)
+
+	c := &Context{}
+	if err := c.ReadEnv(); err != nil {
+		t.Fatalf("got error %q; want nil when the event file isn't needed", err)
+	}
+	if _, err := c.Event(); err == nil {
+		t.Fatal("got nil error reading missing event file")
+	}
+	if err := os.WriteFile(eventPath, []byte(`{"after": "abc123"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	push, err := c.PushEvent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if push.After != "abc123" {
+		t.Errorf("got After %q; want abc123", push.After)
+	}
+}
+
+func TestContext_ReadEnv_bools(t *testing.T) {
+	cases := []struct {
+		value   string
+		want    bool
+		wantErr bool
+	}{
+		{"1", true, false},
+		{"true", true, false},
+		{"0", false, false},
+		{"false", false, false},
+		{"yes", false, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.value, func(t *testing.T) {
+			t.Setenv("RUNNER_DEBUG", tc.value)
+			c := &Context{}
+			err := c.ReadEnv()
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("got nil error; want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.RunnerDebug != tc.want {
+				t.Errorf("got RunnerDebug=%t; want %t", c.RunnerDebug, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import "os"
+
+// envLookup is embedded in the types in this package that implement cli.Env,
+// so that they also implement cli.EnvLookupSetter and read the command's
+// environment rather than the process environment, which tests may isolate.
+//
+// As with ReadEnv, SetLookupEnv isn't promoted from more than one embedded
+// type, so options embedding several of these types need their own
+// SetLookupEnv method that calls each of theirs.
+type envLookup struct {
+	lookup func(string) (string, bool)
+}
+
+// SetLookupEnv sets the function used to look up environment variables, which
+// defaults to os.LookupEnv.
+func (e *envLookup) SetLookupEnv(f func(string) (string, bool)) { e.lookup = f }
+
+func (e *envLookup) getenv(name string) string {
+	if e.lookup == nil {
+		return os.Getenv(name)
+	}
+	v, _ := e.lookup(name)
+	return v
+}
@@ -0,0 +1,114 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	EnvPathEnv  = "GITHUB_ENV"
+	PathPathEnv = "GITHUB_PATH"
+)
+
+// Environment exports environment variables and PATH entries to subsequent
+// steps by appending to $GITHUB_ENV and $GITHUB_PATH. It is intended to be
+// embedded in a command's options so that its ReadEnv method is called.
+//
+// When either file is not set, for example when running locally, the entries
+// that would have been written to it are printed to the fallback writer
+// instead. It implements cli.StdoutSetter, so by default that's the command's
+// stdout, or os.Stdout outside of a command. As with ReadEnv, SetStdout isn't
+// promoted when it's embedded alongside WorkflowCommands.
+type Environment struct {
+	envFilePath  string
+	pathFilePath string
+	stdout       io.Writer
+	fallback     io.Writer
+
+	envW, pathW io.Writer
+	files       []*os.File
+	envLookup
+}
+
+func (e *Environment) ReadEnv() error {
+	e.envFilePath = e.getenv(EnvPathEnv)
+	e.pathFilePath = e.getenv(PathPathEnv)
+	return nil
+}
+
+// SetStdout sets the default fallback writer. It must be called before Open.
+func (e *Environment) SetStdout(w io.Writer) { e.stdout = w }
+
+// SetFallback sets the writer used when $GITHUB_ENV or $GITHUB_PATH is unset,
+// overriding the one set by SetStdout. It must be called before Open.
+func (e *Environment) SetFallback(w io.Writer) { e.fallback = w }
+
+// Open opens $GITHUB_ENV and $GITHUB_PATH for appending.
+func (e *Environment) Open() error {
+	var err error
+	if e.envW, err = e.open(e.envFilePath); err != nil {
+		return err
+	}
+	if e.pathW, err = e.open(e.pathFilePath); err != nil {
+		e.Close()
+		return err
+	}
+	return nil
+}
+
+func (e *Environment) open(path string) (io.Writer, error) {
+	if path == "" {
+		switch {
+		case e.fallback != nil:
+			return e.fallback, nil
+		case e.stdout != nil:
+			return e.stdout, nil
+		}
+		return os.Stdout, nil
+	}
+	f, err := openAppend(path)
+	if err != nil {
+		return nil, err
+	}
+	e.files = append(e.files, f)
+	return f, nil
+}
+
+func (e *Environment) Close() error {
+	var err error
+	for _, f := range e.files {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	e.files, e.envW, e.pathW = nil, nil, nil
+	return err
+}
+
+// Set exports the environment variable name with value.
+func (e *Environment) Set(name, value string) error {
+	if e.envW == nil {
+		return fmt.Errorf("%s not open", EnvPathEnv)
+	}
+	return writeKeyValue(e.envW, name, value)
+}
+
+// AddPath prepends dir to PATH for subsequent steps.
+func (e *Environment) AddPath(dir string) error {
+	if e.pathW == nil {
+		return fmt.Errorf("%s not open", PathPathEnv)
+	}
+	if dir == "" {
+		return fmt.Errorf("path must not be empty")
+	}
+	if strings.ContainsAny(dir, "\r\n") {
+		return fmt.Errorf("path %q must not contain newlines", dir)
+	}
+	_, err := fmt.Fprintln(e.pathW, dir)
+	return err
+}
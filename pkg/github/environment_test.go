@@ -0,0 +1,167 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/composite-action-framework-go/pkg/cli"
+)
+
+func openEnvironment(t *testing.T, envFile, pathFile string, fallback *bytes.Buffer) *Environment {
+	t.Helper()
+	t.Setenv(EnvPathEnv, envFile)
+	t.Setenv(PathPathEnv, pathFile)
+	e := &Environment{}
+	if err := e.ReadEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if fallback != nil {
+		e.SetFallback(fallback)
+	}
+	if err := e.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { e.Close() })
+	return e
+}
+
+func TestEnvironment_appendsToFiles(t *testing.T) {
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, "env")
+	pathFile := filepath.Join(dir, "path")
+	if err := os.WriteFile(envFile, []byte("EXISTING=1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pathFile, []byte("/existing\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fallback := &bytes.Buffer{}
+	e := openEnvironment(t, envFile, pathFile, fallback)
+	if err := e.Set("NAME", "value"); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.AddPath("/new/bin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	assertFile(t, envFile, "EXISTING=1\nNAME=value\n")
+	assertFile(t, pathFile, "/existing\n/new/bin\n")
+	if fallback.Len() != 0 {
+		t.Errorf("got fallback output %q; want none", fallback)
+	}
+	if err := e.Set("NAME", "value"); err == nil {
+		t.Errorf("Set after Close: got nil error; want error")
+	}
+}
+
+func TestEnvironment_fallback(t *testing.T) {
+	fallback := &bytes.Buffer{}
+	e := openEnvironment(t, "", "", fallback)
+	if err := e.Set("NAME", "value"); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.AddPath("/new/bin"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fallback.String(), "NAME=value\n/new/bin\n"; got != want {
+		t.Errorf("got fallback output %q; want %q", got, want)
+	}
+}
+
+type exportOpts struct {
+	Environment
+}
+
+func TestEnvironment_commandStdout(t *testing.T) {
+	t.Setenv(EnvPathEnv, "")
+	t.Setenv(PathPathEnv, "")
+	buf := &bytes.Buffer{}
+	root := cli.RootCommand("root", "root command",
+		cli.LeafCommand("leaf", "leaf command", func(opts *exportOpts) error {
+			if err := opts.Open(); err != nil {
+				return err
+			}
+			defer opts.Close()
+			return opts.Set("NAME", "value")
+		}),
+	)
+	root.SetStdout(buf)
+	if err := root.Execute([]string{"", "leaf"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "NAME=value\n"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func TestEnvironment_SetFallback_overridesStdout(t *testing.T) {
+	stdout, fallback := &bytes.Buffer{}, &bytes.Buffer{}
+	e := &Environment{}
+	e.SetStdout(stdout)
+	e.SetFallback(fallback)
+	if err := e.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	if err := e.Set("NAME", "value"); err != nil {
+		t.Fatal(err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("got stdout output %q; want none", stdout)
+	}
+	if got, want := fallback.String(), "NAME=value\n"; got != want {
+		t.Errorf("got fallback output %q; want %q", got, want)
+	}
+}
+
+func TestEnvironment_Set_multiline(t *testing.T) {
+	fallback := &bytes.Buffer{}
+	e := openEnvironment(t, "", "", fallback)
+	if err := e.Set("NAME", "line1\nline2"); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(fallback.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines; want 4:\n%s", len(lines), fallback)
+	}
+	name, delimiter, ok := strings.Cut(lines[0], "<<")
+	if !ok || name != "NAME" || !strings.HasPrefix(delimiter, delimiterPrefix) {
+		t.Fatalf("malformed first line %q", lines[0])
+	}
+	if lines[1] != "line1" || lines[2] != "line2" || lines[3] != delimiter {
+		t.Errorf("got lines %q; want value lines then closing delimiter %q", lines[1:], delimiter)
+	}
+}
+
+func TestEnvironment_AddPath_invalid(t *testing.T) {
+	fallback := &bytes.Buffer{}
+	e := openEnvironment(t, "", "", fallback)
+	for _, dir := range []string{"", "a\nb", "a\rb"} {
+		if err := e.AddPath(dir); err == nil {
+			t.Errorf("AddPath(%q): got nil error; want error", dir)
+		}
+	}
+	if fallback.Len() != 0 {
+		t.Errorf("got fallback output %q; want none", fallback)
+	}
+}
+
+func assertFile(t *testing.T, path, want string) {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(b); got != want {
+		t.Errorf("%s: got %q; want %q", filepath.Base(path), got, want)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// delimiterPrefix is the same prefix used by the actions toolkit, which makes
+// the heredoc-style entries we write easy to recognise in raw files.
+const delimiterPrefix = "ghadelimiter_"
+
+// writeKeyValue writes a single name/value pair to w in the format understood
+// by the $GITHUB_OUTPUT and $GITHUB_ENV file commands. Single line values are
+// written as name=value, multiline values use a randomly generated heredoc
+// delimiter so the value can't be used to inject further entries.
+func writeKeyValue(w io.Writer, name, value string) error {
+	if name == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+	if strings.ContainsAny(name, "=\r\n") {
+		return fmt.Errorf("name %q must not contain '=' or newlines", name)
+	}
+	if !strings.ContainsAny(value, "\r\n") {
+		_, err := fmt.Fprintf(w, "%s=%s\n", name, value)
+		return err
+	}
+	delimiter, err := newDelimiter()
+	if err != nil {
+		return err
+	}
+	if strings.Contains(name, delimiter) || strings.Contains(value, delimiter) {
+		return fmt.Errorf("value for %q contains the generated delimiter %q", name, delimiter)
+	}
+	_, err = fmt.Fprintf(w, "%s<<%s\n%s\n%s\n", name, delimiter, value, delimiter)
+	return err
+}
+
+func newDelimiter() (string, error) {
+	token, err := randomToken()
+	return delimiterPrefix + token, err
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
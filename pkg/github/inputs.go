@@ -0,0 +1,170 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import (
+	"encoding"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// InputTag is the struct tag used by ReadInputs to name inputs.
+	// Add ",required" to the tag to make the input required.
+	InputTag = "input"
+	// DefaultTag is the struct tag used by ReadInputs to set a default value
+	// for an input that's not set or empty.
+	DefaultTag = "default"
+)
+
+// Inputs implements cli.Env by reading the action inputs described by T's
+// struct tags into Values. See ReadInputs for details.
+type Inputs[T any] struct {
+	Values T
+	envLookup
+}
+
+func (i *Inputs[T]) ReadEnv() error {
+	return readInputs(&i.Values, i.getenv)
+}
+
+// InputEnvVar returns the name of the environment variable that holds the
+// input called name, in the same way as the actions runner.
+func InputEnvVar(name string) string {
+	return "INPUT_" + strings.ToUpper(strings.ReplaceAll(name, " ", "_"))
+}
+
+// GetInput returns the value of the input called name, with surrounding
+// whitespace trimmed.
+func GetInput(name string) string {
+	return strings.TrimSpace(os.Getenv(InputEnvVar(name)))
+}
+
+// ReadInputs populates the fields of the struct pointed to by v which have an
+// `input:"name"` tag from the corresponding INPUT_<NAME> environment variable.
+//
+// Values are trimmed of surrounding whitespace, and an empty value is treated as
+// not set. Unset inputs take the value of the `default` tag if there is one, or
+// else cause an error if the input is required, or else leave the field as-is.
+//
+// Supported field types are strings, bools (parsed using the YAML 1.2 core
+// schema, like the actions toolkit), ints, uints, floats, time.Duration,
+// []string (one item per non-empty line) and types implementing
+// encoding.TextUnmarshaler.
+func ReadInputs(v any) error {
+	return readInputs(v, os.Getenv)
+}
+
+func readInputs(v any, getenv func(string) string) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot read inputs into %T; must be a pointer to a struct", v)
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag, ok := field.Tag.Lookup(InputTag)
+		if !ok || tag == "-" || !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		value := strings.TrimSpace(getenv(InputEnvVar(name)))
+		if value == "" {
+			value = field.Tag.Get(DefaultTag)
+		}
+		if value == "" {
+			if opts == "required" {
+				return fmt.Errorf("input required and not supplied: %s", name)
+			}
+			continue
+		}
+		if err := setValue(rv.Field(i), value); err != nil {
+			return fmt.Errorf("input %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setValue parses value into v according to v's type.
+func setValue(v reflect.Value, value string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(value))
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		v.Set(reflect.ValueOf(parseList(value)).Convert(v.Type()))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// parseBool parses s as a bool according to the YAML 1.2 core schema.
+func parseBool(s string) (bool, error) {
+	switch s {
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	return false, fmt.Errorf("%q is not a valid boolean; must be one of true, True, TRUE, false, False, FALSE", s)
+}
+
+// parseList splits s into lines, trimming whitespace and dropping empty lines.
+func parseList(s string) []string {
+	var list []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			list = append(list, line)
+		}
+	}
+	return list
+}
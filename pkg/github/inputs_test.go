@@ -0,0 +1,89 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/composite-action-framework-go/pkg/testhelpers/assert"
+)
+
+type testInputs struct {
+	Name    string        `input:"name,required"`
+	Debug   bool          `input:"debug"`
+	Count   int           `input:"count" default:"3"`
+	Timeout time.Duration `input:"timeout" default:"1m"`
+	Paths   []string      `input:"paths"`
+	Spaced  string        `input:"with space"`
+	Ignored string
+}
+
+func TestReadInputs(t *testing.T) {
+	cases := []struct {
+		desc    string
+		env     map[string]string
+		want    testInputs
+		wantErr string
+	}{
+		{
+			"required_only",
+			map[string]string{"INPUT_NAME": " blah "},
+			testInputs{Name: "blah", Count: 3, Timeout: time.Minute},
+			"",
+		},
+		{
+			"all",
+			map[string]string{
+				"INPUT_NAME":       "blah",
+				"INPUT_DEBUG":      "True",
+				"INPUT_COUNT":      "5",
+				"INPUT_TIMEOUT":    "5s",
+				"INPUT_PATHS":      "a\n\n  b  \nc\n",
+				"INPUT_WITH_SPACE": "spaced",
+			},
+			testInputs{
+				Name:    "blah",
+				Debug:   true,
+				Count:   5,
+				Timeout: 5 * time.Second,
+				Paths:   []string{"a", "b", "c"},
+				Spaced:  "spaced",
+			},
+			"",
+		},
+		{
+			"required_missing",
+			map[string]string{"INPUT_NAME": "  "},
+			testInputs{},
+			"input required and not supplied: name",
+		},
+		{
+			"invalid_bool",
+			map[string]string{"INPUT_NAME": "blah", "INPUT_DEBUG": "yes"},
+			testInputs{},
+			`input debug: "yes" is not a valid boolean; must be one of true, True, TRUE, false, False, FALSE`,
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			for _, name := range []string{"NAME", "DEBUG", "COUNT", "TIMEOUT", "PATHS", "WITH_SPACE"} {
+				t.Setenv("INPUT_"+name, c.env["INPUT_"+name])
+			}
+			got := &Inputs[testInputs]{}
+			err := got.ReadEnv()
+			if c.wantErr != "" {
+				if err == nil || err.Error() != c.wantErr {
+					t.Fatalf("got error %v; want %q", err, c.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, got.Values, c.want)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import (
+	"encoding"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+)
+
+const (
+	OutputsEnabledFlag = "github-output"
+	OutputsPathEnv     = "GITHUB_OUTPUT"
+
+	// OutputTag is the struct tag used by SetAll to name outputs.
+	OutputTag = "output"
+)
+
+// Outputs writes step outputs to $GITHUB_OUTPUT so that they're available to
+// subsequent steps. It is intended to be embedded in a command's options so
+// that its ReadEnv and Flags methods are called, in the same way as StepSummary.
+//
+// Go doesn't promote ambiguous methods, so if the options embed Outputs
+// alongside another type with ReadEnv, SetLookupEnv or Flags methods, such as
+// StepSummary, neither type's methods are called. In that case the options
+// need their own methods that call those of each embedded type.
+type Outputs struct {
+	enabled  bool
+	filePath string
+	w        io.Writer
+	file     *os.File
+	envLookup
+}
+
+func (o *Outputs) ReadEnv() error {
+	o.filePath = o.getenv(OutputsPathEnv)
+	return nil
+}
+
+func (o *Outputs) Flags(fs *flag.FlagSet) {
+	desc := fmt.Sprintf("write outputs to $%s", OutputsPathEnv)
+	enabledByDefault := o.filePath != ""
+	fs.BoolVar(&o.enabled, OutputsEnabledFlag, enabledByDefault, desc)
+}
+
+// Open opens the outputs file for appending. If outputs are not enabled then
+// Open does nothing and subsequent calls to Set and SetAll are no-ops.
+func (o *Outputs) Open() error {
+	if !o.enabled {
+		return nil
+	}
+	if o.filePath == "" {
+		return fmt.Errorf("%s is empty", OutputsPathEnv)
+	}
+	var err error
+	if o.file, err = openAppend(o.filePath); err != nil {
+		return err
+	}
+	o.w = o.file
+	return nil
+}
+
+func (o *Outputs) Close() error {
+	if o.file == nil {
+		return nil
+	}
+	err := o.file.Close()
+	o.file, o.w = nil, nil
+	return err
+}
+
+// Set writes a single output named name.
+func (o *Outputs) Set(name, value string) error {
+	if o.w == nil {
+		return nil
+	}
+	return writeKeyValue(o.w, name, value)
+}
+
+// SetAll writes an output for each field of the struct v (or pointer to struct)
+// that has an `output:"name"` tag. Adding ",omitempty" to the tag skips fields
+// with zero values. See WriteOutputs for how values are formatted.
+func (o *Outputs) SetAll(v any) error {
+	if o.w == nil {
+		return nil
+	}
+	return WriteOutputs(o.w, v)
+}
+
+// WriteOutputs writes the tagged fields of the struct v to w using the
+// $GITHUB_OUTPUT file format. Strings, bools and numbers are written as-is,
+// types implementing encoding.TextMarshaler or fmt.Stringer use those methods,
+// nil pointers are written as empty values, and anything else is written as
+// JSON.
+func WriteOutputs(w io.Writer, v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return fmt.Errorf("cannot write outputs from nil %T", v)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot write outputs from %T; must be a struct", v)
+	}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag, ok := field.Tag.Lookup(OutputTag)
+		if !ok || tag == "-" || !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		fv := rv.Field(i)
+		if opts == "omitempty" && fv.IsZero() {
+			continue
+		}
+		value, err := formatValue(fv)
+		if err != nil {
+			return fmt.Errorf("formatting output %q: %w", name, err)
+		}
+		if err := writeKeyValue(w, name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatValue(v reflect.Value) (string, error) {
+	// Nil pointers may implement the interfaces below, but their methods
+	// usually can't handle a nil receiver.
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return "", nil
+	}
+	switch i := v.Interface().(type) {
+	case encoding.TextMarshaler:
+		b, err := i.MarshalText()
+		return string(b), err
+	case fmt.Stringer:
+		return i.String(), nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v.Interface()), nil
+	}
+	b, err := json.Marshal(v.Interface())
+	return string(b), err
+}
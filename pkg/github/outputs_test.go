@@ -0,0 +1,124 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteOutputs(t *testing.T) {
+	type outputs struct {
+		Name     string        `output:"name"`
+		Count    int           `output:"count"`
+		OK       bool          `output:"ok"`
+		Duration time.Duration `output:"duration"`
+		List     []string      `output:"list"`
+		URL      *url.URL      `output:"url"`
+		NilURL   *url.URL      `output:"nil_url"`
+		Empty    string        `output:"empty,omitempty"`
+		Skipped  string        `output:"-"`
+		Untagged string
+	}
+	buf := &bytes.Buffer{}
+	err := WriteOutputs(buf, &outputs{
+		Name:     "blah",
+		Count:    3,
+		OK:       true,
+		Duration: time.Second,
+		List:     []string{"a", "b"},
+		URL:      &url.URL{Scheme: "https", Host: "example.com"},
+		Skipped:  "skipped",
+		Untagged: "untagged",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	want := "name=blah\ncount=3\nok=true\nduration=1s\nlist=[\"a\",\"b\"]\nurl=https://example.com\nnil_url=\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteKeyValue_multiline(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := writeKeyValue(buf, "name", "line1\nline2"); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines; want 4:\n%s", len(lines), buf)
+	}
+	name, delimiter, ok := strings.Cut(lines[0], "<<")
+	if !ok || name != "name" || !strings.HasPrefix(delimiter, delimiterPrefix) {
+		t.Fatalf("malformed first line %q", lines[0])
+	}
+	if lines[1] != "line1" || lines[2] != "line2" {
+		t.Errorf("got value lines %q; want [line1 line2]", lines[1:3])
+	}
+	if lines[3] != delimiter {
+		t.Errorf("got closing delimiter %q; want %q", lines[3], delimiter)
+	}
+}
+
+func TestWriteKeyValue_invalidName(t *testing.T) {
+	for _, name := range []string{"", "a=b", "a\nb"} {
+		if err := writeKeyValue(&bytes.Buffer{}, name, "value"); err == nil {
+			t.Errorf("name %q: got nil error; want error", name)
+		}
+	}
+}
+
+// outputsAndSummary shows how to embed Outputs alongside StepSummary.
+type outputsAndSummary struct {
+	Outputs
+	StepSummary
+}
+
+func (o *outputsAndSummary) ReadEnv() error {
+	return errors.Join(o.Outputs.ReadEnv(), o.StepSummary.ReadEnv())
+}
+
+func (o *outputsAndSummary) SetLookupEnv(f func(string) (string, bool)) {
+	o.Outputs.SetLookupEnv(f)
+	o.StepSummary.SetLookupEnv(f)
+}
+
+func (o *outputsAndSummary) Flags(fs *flag.FlagSet) {
+	o.Outputs.Flags(fs)
+	o.StepSummary.Flags(fs)
+}
+
+func TestOutputs_embeddedWithStepSummary(t *testing.T) {
+	type ambiguous struct {
+		Outputs
+		StepSummary
+	}
+	if _, ok := any(&ambiguous{}).(interface{ ReadEnv() error }); ok {
+		t.Fatal("ReadEnv is promoted from ambiguous embedded types; update the Outputs doc comment")
+	}
+
+	env := map[string]string{OutputsPathEnv: "outputs", StepSummaryPathEnv: "summary"}
+	o := &outputsAndSummary{}
+	o.SetLookupEnv(func(name string) (string, bool) { v, ok := env[name]; return v, ok })
+	if err := o.ReadEnv(); err != nil {
+		t.Fatal(err)
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	o.Flags(fs)
+	if o.Outputs.filePath != "outputs" || o.StepSummary.filePath != "summary" {
+		t.Errorf("got paths %q and %q; want outputs and summary", o.Outputs.filePath, o.StepSummary.filePath)
+	}
+	for _, name := range []string{OutputsEnabledFlag, StepSummaryEnabledFlag} {
+		if f := fs.Lookup(name); f == nil || f.DefValue != "true" {
+			t.Errorf("flag -%s not defined, or not enabled by default", name)
+		}
+	}
+}
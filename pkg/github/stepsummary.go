@@ -20,10 +20,11 @@ type StepSummary struct {
 	enabled  bool
 	filePath string
 	file     *os.File
+	envLookup
 }
 
 func (gss *StepSummary) ReadEnv() error {
-	gss.filePath = os.Getenv(StepSummaryPathEnv)
+	gss.filePath = gss.getenv(StepSummaryPathEnv)
 	return nil
 }
 
@@ -1,7 +1,158 @@
 // Copyright IBM Corp. 2022, 2025
 // SPDX-License-Identifier: MPL-2.0
 
+// Package stepsummary builds markdown for GitHub Actions job summaries.
+//
+// Build a StepSummary using its methods, then render it to the writer
+// returned by github.StepSummary.Open using WriteTo, or to a string using
+// String. All methods return the StepSummary so calls can be chained.
 package stepsummary
 
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
 type StepSummary struct {
+	blocks []string
+}
+
+// New returns an empty StepSummary.
+func New() *StepSummary {
+	return &StepSummary{}
+}
+
+func (s *StepSummary) add(block string) *StepSummary {
+	s.blocks = append(s.blocks, strings.TrimRight(block, "\n"))
+	return s
+}
+
+// Heading adds a heading at level, which is clamped between 1 and 6.
+func (s *StepSummary) Heading(level int, text string) *StepSummary {
+	if level < 1 {
+		level = 1
+	} else if level > 6 {
+		level = 6
+	}
+	return s.add(strings.Repeat("#", level) + " " + text)
+}
+
+// Paragraph adds a paragraph of markdown text.
+func (s *StepSummary) Paragraph(text string) *StepSummary {
+	return s.add(text)
+}
+
+// List adds a bulleted list with one item per entry in items.
+func (s *StepSummary) List(items ...string) *StepSummary {
+	b := &strings.Builder{}
+	for _, i := range items {
+		fmt.Fprintf(b, "- %s\n", i)
+	}
+	return s.add(b.String())
+}
+
+// CodeBlock adds a fenced code block, highlighted as lang if not empty.
+func (s *StepSummary) CodeBlock(lang, code string) *StepSummary {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return s.add(fmt.Sprintf("%s%s\n%s\n%s", fence, lang, strings.TrimRight(code, "\n"), fence))
+}
+
+// Image adds an image on its own line.
+func (s *StepSummary) Image(src, alt string) *StepSummary {
+	return s.add(Image(src, alt))
+}
+
+// Details adds a collapsible section with summary as its title, and the
+// content built by body inside.
+func (s *StepSummary) Details(summary string, body func(*StepSummary)) *StepSummary {
+	inner := New()
+	body(inner)
+	return s.add(fmt.Sprintf("<details><summary>%s</summary>\n\n%s\n\n</details>", summary, inner.join()))
+}
+
+// Rule adds a horizontal rule.
+func (s *StepSummary) Rule() *StepSummary {
+	return s.add("---")
+}
+
+// Table adds a table to s. Like cli.TabWrite, header and the strings returned
+// by printer are tab-separated cells, which means the same printer can be used
+// to render data both for a terminal and a step summary.
+func Table[T any](s *StepSummary, header string, data []T, printer func(datum T) string) *StepSummary {
+	headings := strings.Split(header, "\t")
+	b := &strings.Builder{}
+	writeRow(b, headings)
+	seps := make([]string, len(headings))
+	for i := range seps {
+		seps[i] = "---"
+	}
+	writeRow(b, seps)
+	for _, d := range data {
+		writeRow(b, strings.Split(printer(d), "\t"))
+	}
+	return s.add(b.String())
+}
+
+func writeRow(w io.Writer, cells []string) {
+	for i, c := range cells {
+		cells[i] = escapeCell(c)
+	}
+	fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+}
+
+func escapeCell(s string) string {
+	return strings.NewReplacer(
+		"|", `\|`,
+		"\r\n", "<br>",
+		"\n", "<br>",
+	).Replace(s)
+}
+
+// Link returns an inline markdown link.
+func Link(text, url string) string {
+	return fmt.Sprintf("[%s](%s)", text, url)
+}
+
+// Image returns an inline markdown image.
+func Image(src, alt string) string {
+	return fmt.Sprintf("![%s](%s)", alt, src)
+}
+
+// Code returns text as inline code.
+func Code(text string) string {
+	fence := "`"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		text = " " + text + " "
+	}
+	return fence + text + fence
+}
+
+// String returns the rendered markdown.
+func (s *StepSummary) String() string {
+	if len(s.blocks) == 0 {
+		return ""
+	}
+	return s.join() + "\n"
+}
+
+func (s *StepSummary) join() string {
+	return strings.Join(s.blocks, "\n\n")
+}
+
+// WriteTo writes the rendered markdown to w. If w is nil, which is what
+// github.StepSummary.Open returns when the step summary is disabled, then
+// nothing is written.
+func (s *StepSummary) WriteTo(w io.Writer) (int64, error) {
+	if w == nil {
+		return 0, nil
+	}
+	n, err := io.WriteString(w, s.String())
+	return int64(n), err
 }
@@ -0,0 +1,40 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package stepsummary
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/composite-action-framework-go/pkg/testhelpers/goldenfile"
+)
+
+func TestStepSummary(t *testing.T) {
+	type result struct {
+		name, status string
+	}
+	results := []result{
+		{"build", "passed"},
+		{"test | lint", "failed\nsee logs"},
+	}
+	s := New().
+		Heading(2, "Results").
+		Paragraph("Built from " + Link("main", "https://example.com/main") + " at " + Code("abc123") + ".")
+	Table(s, "Name\tStatus", results, func(r result) string {
+		return fmt.Sprintf("%s\t%s", r.name, r.status)
+	})
+	s.Details("Logs", func(d *StepSummary) {
+		d.CodeBlock("text", "line 1\n```\nline 2\n")
+		d.List("one", "two")
+	}).
+		Rule().
+		Image("https://example.com/badge.svg", "badge")
+
+	goldenfile.Do(t, func(got *os.File) {
+		if _, err := s.WriteTo(got); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,181 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/composite-action-framework-go/pkg/internal/workflowcommand"
+)
+
+// WorkflowCommands writes workflow commands (annotations, log groups, masks etc.)
+// which are interpreted by the runner when printed to stdout.
+//
+// It implements cli.StdoutSetter, so when embedded in a command's options it
+// writes to the command's configured stdout, allowing output to be captured
+// in tests via Command.SetStdout. Otherwise it writes to os.Stdout.
+type WorkflowCommands struct {
+	w io.Writer
+}
+
+// NewWorkflowCommands returns a WorkflowCommands that writes to w.
+func NewWorkflowCommands(w io.Writer) *WorkflowCommands {
+	return &WorkflowCommands{w: w}
+}
+
+func (wc *WorkflowCommands) SetStdout(w io.Writer) { wc.w = w }
+
+func (wc *WorkflowCommands) writer() io.Writer {
+	if wc.w == nil {
+		return os.Stdout
+	}
+	return wc.w
+}
+
+// Property is a single key/value property of a workflow command.
+type Property struct {
+	Key, Value string
+}
+
+// Issue writes the workflow command name with the given properties and message.
+// The message and property values are escaped.
+func (wc *WorkflowCommands) Issue(name string, props []Property, message string) error {
+	_, err := io.WriteString(wc.writer(), FormatCommand(name, props, message)+"\n")
+	return err
+}
+
+// FormatCommand returns the workflow command name with the given properties
+// and message formatted as a single line, not including the trailing newline.
+func FormatCommand(name string, props []Property, message string) string {
+	b := &strings.Builder{}
+	b.WriteString("::")
+	b.WriteString(name)
+	for i, p := range props {
+		if i == 0 {
+			b.WriteString(" ")
+		} else {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(b, "%s=%s", p.Key, workflowcommand.EscapeProperty(p.Value))
+	}
+	b.WriteString("::")
+	b.WriteString(workflowcommand.EscapeData(message))
+	return b.String()
+}
+
+// Annotation holds the optional location properties of an error, warning or
+// notice annotation. Zero-valued fields are omitted.
+type Annotation struct {
+	Title     string
+	File      string
+	Line      int
+	EndLine   int
+	Col       int
+	EndColumn int
+}
+
+func (a Annotation) properties() []Property {
+	var props []Property
+	addString := func(k, v string) {
+		if v != "" {
+			props = append(props, Property{k, v})
+		}
+	}
+	addInt := func(k string, v int) {
+		if v != 0 {
+			props = append(props, Property{k, strconv.Itoa(v)})
+		}
+	}
+	addString("title", a.Title)
+	addString("file", a.File)
+	addInt("line", a.Line)
+	addInt("endLine", a.EndLine)
+	addInt("col", a.Col)
+	addInt("endColumn", a.EndColumn)
+	return props
+}
+
+// Error writes an error annotation.
+func (wc *WorkflowCommands) Error(message string) error {
+	return wc.ErrorAt(Annotation{}, message)
+}
+
+// ErrorAt writes an error annotation with location and title properties.
+func (wc *WorkflowCommands) ErrorAt(a Annotation, message string) error {
+	return wc.Issue("error", a.properties(), message)
+}
+
+// Warning writes a warning annotation.
+func (wc *WorkflowCommands) Warning(message string) error {
+	return wc.WarningAt(Annotation{}, message)
+}
+
+// WarningAt writes a warning annotation with location and title properties.
+func (wc *WorkflowCommands) WarningAt(a Annotation, message string) error {
+	return wc.Issue("warning", a.properties(), message)
+}
+
+// Notice writes a notice annotation.
+func (wc *WorkflowCommands) Notice(message string) error {
+	return wc.NoticeAt(Annotation{}, message)
+}
+
+// NoticeAt writes a notice annotation with location and title properties.
+func (wc *WorkflowCommands) NoticeAt(a Annotation, message string) error {
+	return wc.Issue("notice", a.properties(), message)
+}
+
+// Debug writes a debug message, which is only shown when step debug logging
+// is enabled.
+func (wc *WorkflowCommands) Debug(message string) error {
+	return wc.Issue("debug", nil, message)
+}
+
+// AddMask masks value in all subsequent log output.
+func (wc *WorkflowCommands) AddMask(value string) error {
+	return wc.Issue("add-mask", nil, value)
+}
+
+// Group starts a collapsible log group, which lasts until EndGroup is called.
+func (wc *WorkflowCommands) Group(title string) error {
+	return wc.Issue("group", nil, title)
+}
+
+func (wc *WorkflowCommands) EndGroup() error {
+	return wc.Issue("endgroup", nil, "")
+}
+
+// WithGroup runs do inside a log group titled title.
+func (wc *WorkflowCommands) WithGroup(title string, do func() error) error {
+	if err := wc.Group(title); err != nil {
+		return err
+	}
+	doErr := do()
+	if err := wc.EndGroup(); err != nil && doErr == nil {
+		return err
+	}
+	return doErr
+}
+
+// StopCommands stops the runner from processing workflow commands while do
+// runs, so that arbitrary output can't be interpreted as commands. Command
+// processing is resumed afterwards using a randomly generated token.
+func (wc *WorkflowCommands) StopCommands(do func() error) error {
+	token, err := randomToken()
+	if err != nil {
+		return err
+	}
+	if err := wc.Issue("stop-commands", nil, token); err != nil {
+		return err
+	}
+	doErr := do()
+	if err := wc.Issue(token, nil, ""); err != nil && doErr == nil {
+		return err
+	}
+	return doErr
+}
@@ -0,0 +1,70 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/composite-action-framework-go/pkg/cli"
+)
+
+func TestFormatCommand(t *testing.T) {
+	cases := []struct {
+		desc    string
+		name    string
+		props   []Property
+		message string
+		want    string
+	}{
+		{"no_props", "debug", nil, "hello", "::debug::hello"},
+		{"empty_message", "endgroup", nil, "", "::endgroup::"},
+		{"escaped_message", "error", nil, "50%\r\ndone", "::error::50%25%0D%0Adone"},
+		{
+			"props",
+			"error",
+			Annotation{File: "a.go", Line: 3, Col: 7}.properties(),
+			"bad",
+			"::error file=a.go,line=3,col=7::bad",
+		},
+		{
+			"escaped_props",
+			"warning",
+			Annotation{Title: "a:b,c%\n"}.properties(),
+			"msg",
+			"::warning title=a%3Ab%2Cc%25%0A::msg",
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			if got := FormatCommand(c.name, c.props, c.message); got != c.want {
+				t.Errorf("got %q; want %q", got, c.want)
+			}
+		})
+	}
+}
+
+type annotateOpts struct {
+	WorkflowCommands
+}
+
+func TestWorkflowCommands_commandStdout(t *testing.T) {
+	buf := &bytes.Buffer{}
+	root := cli.RootCommand("root", "root command",
+		cli.LeafCommand("leaf", "leaf command", func(opts *annotateOpts) error {
+			return opts.WithGroup("group", func() error {
+				return opts.NoticeAt(Annotation{File: "main.go"}, "hi")
+			})
+		}),
+	)
+	root.SetStdout(buf)
+	if err := root.Execute([]string{"", "leaf"}); err != nil {
+		t.Fatal(err)
+	}
+	want := "::group::group\n::notice file=main.go::hi\n::endgroup::\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Package workflowcommand escapes values for use in GitHub Actions workflow
+// commands. It's shared by the github package, which writes workflow commands,
+// and the cli package, which writes a few itself but can't import github.
+package workflowcommand
+
+import "strings"
+
+var (
+	dataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	propertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+// EscapeData escapes s for use as the message of a workflow command.
+func EscapeData(s string) string { return dataEscaper.Replace(s) }
+
+// EscapeProperty escapes s for use as a property value of a workflow command.
+func EscapeProperty(s string) string { return propertyEscaper.Replace(s) }
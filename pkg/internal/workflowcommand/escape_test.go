@@ -0,0 +1,24 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package workflowcommand
+
+import "testing"
+
+func TestEscape(t *testing.T) {
+	cases := []struct {
+		in, wantData, wantProperty string
+	}{
+		{"plain", "plain", "plain"},
+		{"50%\r\ndone", "50%25%0D%0Adone", "50%25%0D%0Adone"},
+		{"a:b,c", "a:b,c", "a%3Ab%2Cc"},
+	}
+	for _, c := range cases {
+		if got := EscapeData(c.in); got != c.wantData {
+			t.Errorf("EscapeData(%q) = %q; want %q", c.in, got, c.wantData)
+		}
+		if got := EscapeProperty(c.in); got != c.wantProperty {
+			t.Errorf("EscapeProperty(%q) = %q; want %q", c.in, got, c.wantProperty)
+		}
+	}
+}
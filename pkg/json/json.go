@@ -12,10 +12,29 @@ import (
 	"github.com/hashicorp/composite-action-framework-go/pkg/fs"
 )
 
-func Read[T any](r io.Reader) (T, error) {
+// ReadOption configures how JSON is read.
+type ReadOption func(*readSettings)
+
+type readSettings struct {
+	allowUnknownFields bool
+}
+
+// AllowUnknownFields allows the JSON being read to contain fields that don't
+// exist in the type being read into. By default this is an error.
+func AllowUnknownFields() ReadOption {
+	return func(s *readSettings) { s.allowUnknownFields = true }
+}
+
+func Read[T any](r io.Reader, opts ...ReadOption) (T, error) {
+	s := &readSettings{}
+	for _, o := range opts {
+		o(s)
+	}
 	v := new(T)
 	d := json.NewDecoder(r)
-	d.DisallowUnknownFields()
+	if !s.allowUnknownFields {
+		d.DisallowUnknownFields()
+	}
 	err := d.Decode(v)
 	return *v, err
 }
@@ -45,24 +64,24 @@ func WriteFile(filename string, v any, fsOpts ...fs.Option) error {
 	return closeErr
 }
 
-func ReadFile[T any](filename string) (T, error) {
+func ReadFile[T any](filename string, opts ...ReadOption) (T, error) {
 	f, err := os.Open(filename)
 	if err != nil {
 		return *(new(T)), err
 	}
 	var closeErr error
 	defer func() { closeErr = f.Close() }()
-	v, err := Read[T](f)
+	v, err := Read[T](f, opts...)
 	if err != nil {
 		return v, err
 	}
 	return v, closeErr
 }
 
-func ReadBytes[T any](jsonBytes []byte) (T, error) {
-	return Read[T](bytes.NewBuffer(jsonBytes))
+func ReadBytes[T any](jsonBytes []byte, opts ...ReadOption) (T, error) {
+	return Read[T](bytes.NewBuffer(jsonBytes), opts...)
 }
 
-func ReadString[T any](jsonString string) (T, error) {
-	return ReadBytes[T]([]byte(jsonString))
+func ReadString[T any](jsonString string, opts ...ReadOption) (T, error) {
+	return ReadBytes[T]([]byte(jsonString), opts...)
 }
@@ -0,0 +1,157 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Package clitest runs cli commands in-process for testing, with their
+// environment, stdin and working directory controlled by the test, and their
+// output, error and exit code captured for assertions.
+package clitest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/composite-action-framework-go/pkg/cli"
+	"github.com/hashicorp/composite-action-framework-go/pkg/testhelpers/goldenfile"
+)
+
+type settings struct {
+	env        map[string]string
+	inheritEnv bool
+	stdin      io.Reader
+	dir        string
+}
+
+type Option func(*settings)
+
+// WithEnv sets environment variables visible to the command. Unless
+// WithInheritedEnv is used, these are the only variables it can see.
+//
+// The environment is isolated from the process environment by
+// Command.SetLookupEnv, so it's only seen by the cli package itself and by
+// options implementing cli.EnvLookupSetter, which include the types in the
+// github package that implement cli.Env.
+func WithEnv(env map[string]string) Option {
+	return func(s *settings) {
+		for k, v := range env {
+			s.env[k] = v
+		}
+	}
+}
+
+// WithInheritedEnv makes the process environment visible to the command,
+// underneath any variables set by WithEnv.
+func WithInheritedEnv() Option {
+	return func(s *settings) { s.inheritEnv = true }
+}
+
+// WithStdin sets the command's stdin.
+func WithStdin(r io.Reader) Option {
+	return func(s *settings) { s.stdin = r }
+}
+
+// WithStdinString sets the command's stdin to read from in.
+func WithStdinString(in string) Option {
+	return WithStdin(strings.NewReader(in))
+}
+
+// WithDir runs the command in dir. Because the working directory belongs to the
+// process, tests using this option can't be run in parallel.
+func WithDir(dir string) Option {
+	return func(s *settings) { s.dir = dir }
+}
+
+// Result is the outcome of running a command.
+type Result struct {
+	Stdout, Stderr string
+	// Err is the error returned by executing the command.
+	Err error
+	// ExitCode is the code cli.Main would have exited with.
+	ExitCode int
+}
+
+// Run runs root with args, which shouldn't include the program name. Errors are
+// reported to the captured stderr in the same way as cli.Main.
+func Run(t *testing.T, root *cli.Command, args []string, opts ...Option) *Result {
+	t.Helper()
+	s := &settings{env: map[string]string{}, stdin: strings.NewReader("")}
+	for _, o := range opts {
+		o(s)
+	}
+	if s.dir != "" {
+		t.Chdir(s.dir)
+	}
+	prevStdout, prevStderr, prevStdin := root.Stdout(), root.Stderr(), root.Stdin()
+	prevLookupEnv := root.LookupEnvFunc()
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	root.SetStdout(stdout)
+	root.SetStderr(stderr)
+	root.SetStdin(s.stdin)
+	root.SetLookupEnv(s.lookupEnv)
+	defer func() {
+		root.SetStdout(prevStdout)
+		root.SetStderr(prevStderr)
+		root.SetStdin(prevStdin)
+		root.SetLookupEnv(prevLookupEnv)
+	}()
+	err := root.Execute(append([]string{root.Name()}, args...))
+	code := cli.ReportError(root, err)
+	return &Result{Stdout: stdout.String(), Stderr: stderr.String(), Err: err, ExitCode: code}
+}
+
+func (s *settings) lookupEnv(name string) (string, bool) {
+	if v, ok := s.env[name]; ok {
+		return v, true
+	}
+	if s.inheritEnv {
+		return os.LookupEnv(name)
+	}
+	return "", false
+}
+
+// String describes r for use in failure messages and golden files.
+func (r *Result) String() string {
+	return fmt.Sprintf("exit code: %d\n\nstdout:\n%s\nstderr:\n%s", r.ExitCode, r.Stdout, r.Stderr)
+}
+
+// AssertExitCode fails the test if r's exit code isn't want.
+func (r *Result) AssertExitCode(t *testing.T, want int) {
+	t.Helper()
+	if r.ExitCode != want {
+		t.Errorf("got exit code %d; want %d\n%s", r.ExitCode, want, r)
+	}
+}
+
+// AssertSuccess fails the test if the command failed.
+func (r *Result) AssertSuccess(t *testing.T) {
+	t.Helper()
+	if r.Err != nil {
+		t.Fatalf("command failed: %s\n%s", r.Err, r)
+	}
+}
+
+// AssertStdoutGolden asserts that r's stdout matches the test's golden file,
+// see goldenfile.
+func (r *Result) AssertStdoutGolden(t *testing.T) {
+	t.Helper()
+	assertGolden(t, r.Stdout)
+}
+
+// AssertGolden asserts that r's exit code, stdout and stderr together match the
+// test's golden file, see goldenfile.
+func (r *Result) AssertGolden(t *testing.T) {
+	t.Helper()
+	assertGolden(t, r.String())
+}
+
+func assertGolden(t *testing.T, s string) {
+	t.Helper()
+	goldenfile.Do(t, func(got *os.File) {
+		if _, err := got.WriteString(s); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
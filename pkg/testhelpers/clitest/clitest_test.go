@@ -0,0 +1,133 @@
+// Copyright IBM Corp. 2022, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package clitest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/composite-action-framework-go/pkg/cli"
+	"github.com/hashicorp/composite-action-framework-go/pkg/github"
+)
+
+type greetOpts struct {
+	name, greeting string
+}
+
+func (o *greetOpts) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&o.name, "name", "world", "who to greet")
+}
+
+func (o *greetOpts) Args(al *cli.ArgList) {
+	cli.OptionalArg(al, &o.greeting, "GREETING", cli.StringParser, "hello")
+}
+
+func newRoot() *cli.Command {
+	var leaf *cli.Command
+	leaf = cli.LeafCommand("say", "say a greeting", func(o *greetOpts) error {
+		in, err := io.ReadAll(leaf.Stdin())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(leaf.Stdout(), "%s %s%s\n", o.greeting, o.name, in)
+		return nil
+	}).WithFlagEnv()
+	return cli.RootCommand("greet", "greet people", leaf)
+}
+
+func TestRun(t *testing.T) {
+	t.Setenv("GREET_SAY_NAME", "process")
+	cases := []struct {
+		desc string
+		args []string
+		opts []Option
+		code int
+	}{
+		{"defaults", []string{"say"}, nil, cli.ExitCodeOK},
+		{"isolated env", []string{"say", "hi"}, []Option{WithEnv(map[string]string{"GREET_SAY_NAME": "env"})}, cli.ExitCodeOK},
+		{"inherited env", []string{"say"}, []Option{WithInheritedEnv()}, cli.ExitCodeOK},
+		{"stdin", []string{"say", "-name", "flag"}, []Option{WithStdinString("!")}, cli.ExitCodeOK},
+		{"usage error", []string{"say", "-nope"}, nil, cli.ExitCodeUsage},
+		{"unknown subcommand", []string{"shout"}, nil, cli.ExitCodeUsage},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			r := Run(t, newRoot(), c.args, c.opts...)
+			r.AssertExitCode(t, c.code)
+			r.AssertGolden(t)
+		})
+	}
+}
+
+func TestRun_dir(t *testing.T) {
+	dir := t.TempDir()
+	var wd string
+	root := cli.RootCommand("root", "root",
+		cli.LeafCommand("pwd", "print dir", func(*cli.None) error {
+			var err error
+			wd, err = os.Getwd()
+			return err
+		}),
+	)
+	r := Run(t, root, []string{"pwd"}, WithDir(dir))
+	r.AssertSuccess(t)
+	got, _ := filepath.EvalSymlinks(wd)
+	if want, _ := filepath.EvalSymlinks(dir); got != want {
+		t.Errorf("got dir %q; want %q", got, want)
+	}
+}
+
+type outputOpts struct {
+	github.Outputs
+}
+
+func TestRun_githubEnv(t *testing.T) {
+	dir := t.TempDir()
+	processOutput := filepath.Join(dir, "process")
+	isolatedOutput := filepath.Join(dir, "isolated")
+	t.Setenv(github.OutputsPathEnv, processOutput)
+	root := cli.RootCommand("root", "root",
+		cli.LeafCommand("out", "write an output", func(o *outputOpts) error {
+			if err := o.Open(); err != nil {
+				return err
+			}
+			defer o.Close()
+			return o.Set("name", "value")
+		}),
+	)
+	r := Run(t, root, []string{"out"}, WithEnv(map[string]string{github.OutputsPathEnv: isolatedOutput}))
+	r.AssertSuccess(t)
+	b, err := os.ReadFile(isolatedOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), "name=value\n"; got != want {
+		t.Errorf("got output file %q; want %q", got, want)
+	}
+	if _, err := os.Stat(processOutput); !os.IsNotExist(err) {
+		t.Errorf("output written to $%s from the process environment", github.OutputsPathEnv)
+	}
+}
+
+func TestRun_restoresIO(t *testing.T) {
+	root := newRoot()
+	stdout, stderr, stdin := &bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}
+	root.SetStdout(stdout)
+	root.SetStderr(stderr)
+	root.SetStdin(stdin)
+	root.SetLookupEnv(func(name string) (string, bool) { return "outer", name == "NAME" })
+	Run(t, root, []string{"say"}, WithEnv(map[string]string{"NAME": "inner"})).AssertSuccess(t)
+	if root.Stdout() != stdout || root.Stderr() != stderr || root.Stdin() != stdin {
+		t.Errorf("Run didn't restore the command's stdout, stderr and stdin")
+	}
+	if v, ok := root.LookupEnv("NAME"); !ok || v != "outer" {
+		t.Errorf("got $NAME %q, %t after Run; want the command's own lookup to be restored", v, ok)
+	}
+}
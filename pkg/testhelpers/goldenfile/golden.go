@@ -20,10 +20,12 @@ package goldenfile
 
 import (
 	"flag"
+	"io"
 	"io/fs"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -37,7 +39,7 @@ func new(t *testing.T) *GF {
 		t:              t,
 		actualFile:     nil,
 		goldenFileName: filepath.Join("testdata", t.Name()+".golden"),
-		actualFileName: t.Name() + ".actual",
+		actualFileName: strings.ReplaceAll(t.Name(), "/", "_") + ".actual",
 	}
 }
 
@@ -115,6 +117,10 @@ func (gf *GF) readActual() string {
 	if gf.actualFile == nil {
 		gf.t.Fatal("CreateActual() must be called before ReadActual()")
 	}
+	// The file action leaves the offset at the end of what it wrote.
+	if _, err := gf.actualFile.Seek(0, io.SeekStart); err != nil {
+		gf.t.Fatal(err)
+	}
 	readBytes, err := ioutil.ReadAll(gf.actualFile)
 	if err != nil {
 		gf.t.Fatal(err)